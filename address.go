@@ -1,17 +1,13 @@
-//go:generate go run generator/generate.go
+//go:generate go run ./generator
 
 // Package address is a library that validates and formats addresses using data generated from Google's Address
 // Data Service.
 package address
 
 import (
-	"fmt"
+	"slices"
 	"sort"
 	"strings"
-
-	"golang.org/x/text/collate"
-	textLanguage "golang.org/x/text/language"
-	"golang.org/x/text/language/display"
 )
 
 type formatData struct {
@@ -26,6 +22,11 @@ type formatData struct {
 	AdministrativeAreaPostalKey string
 	PostCode                    string
 	SortingCode                 string
+
+	AdministrativeAreaNameType FieldName
+	LocalityNameType           FieldName
+	DependentLocalityNameType  FieldName
+	PostCodeNameType           FieldName
 }
 
 // Address represents a valid address made up of its child components.
@@ -46,7 +47,7 @@ func (a Address) IsZero() bool {
 	return a.Country == "" && a.Name == "" && a.Organization == "" && len(a.StreetAddress) <= 0 && a.DependentLocality == "" && a.Locality == "" && a.AdministrativeArea == "" && a.PostCode == "" && a.SortingCode == ""
 }
 
-func (a Address) toFormatData(countryData country, language string) formatData {
+func (a Address) toFormatData(registry *Registry, countryData country, language string) formatData {
 
 	f := formatData{
 		Name:               a.Name,
@@ -57,6 +58,11 @@ func (a Address) toFormatData(countryData country, language string) formatData {
 		AdministrativeArea: a.AdministrativeArea,
 		PostCode:           a.PostCode,
 		SortingCode:        a.SortingCode,
+
+		AdministrativeAreaNameType: countryData.AdministrativeAreaNameType,
+		LocalityNameType:           countryData.LocalityNameType,
+		DependentLocalityNameType:  countryData.DependentLocalityNameType,
+		PostCodeNameType:           countryData.PostCodeNameType,
 	}
 
 	for _, addressLine := range a.StreetAddress {
@@ -67,35 +73,24 @@ func (a Address) toFormatData(countryData country, language string) formatData {
 		}
 	}
 
-	normalizedLanguage := generated.normalizeLanguage(countryData.ID, language)
-
-	namer := display.Regions(textLanguage.MustParse(normalizedLanguage))
-
-	country := textLanguage.MustParseRegion(countryData.ID)
-
-	if namer != nil {
-		f.Country = namer.Name(country)
-	} else {
-		namer = display.Regions(textLanguage.English)
-		f.Country = namer.Name(country)
-	}
+	f.Country = registry.countryName(countryData.ID, registry.normalizeLanguage(countryData.ID, language))
 
 	if a.AdministrativeArea != "" {
-		if adminAreaName := generated.getAdministrativeAreaName(a.Country, a.AdministrativeArea, language); adminAreaName != "" {
+		if adminAreaName := registry.data.getAdministrativeAreaName(a.Country, a.AdministrativeArea, language); adminAreaName != "" {
 			f.AdministrativeArea = adminAreaName
 		}
 
-		f.AdministrativeAreaPostalKey = generated.getAdministrativeAreaPostalKey(a.Country, a.AdministrativeArea)
+		f.AdministrativeAreaPostalKey = registry.data.getAdministrativeAreaPostalKey(a.Country, a.AdministrativeArea)
 	}
 
 	if a.Locality != "" {
-		if localityName := generated.getLocalityName(a.Country, a.AdministrativeArea, a.Locality, language); localityName != "" {
+		if localityName := registry.data.getLocalityName(a.Country, a.AdministrativeArea, a.Locality, language); localityName != "" {
 			f.Locality = localityName
 		}
 	}
 
 	if a.DependentLocality != "" {
-		if dependentLocalityName := generated.getDependentLocalityName(a.Country, a.AdministrativeArea, a.Locality, a.DependentLocality, language); dependentLocalityName != "" {
+		if dependentLocalityName := registry.data.getDependentLocalityName(a.Country, a.AdministrativeArea, a.Locality, a.DependentLocality, language); dependentLocalityName != "" {
 			f.DependentLocality = dependentLocalityName
 		}
 	}
@@ -103,20 +98,36 @@ func (a Address) toFormatData(countryData country, language string) formatData {
 	return f
 }
 
-// NewValid creates a new Address. If the address is invalid, an error is returned.
-// In the case where an error is returned, the error is a hashicorp/go-multierror (https://github.com/hashicorp/go-multierror).
-// You can use a type switch to get a list of validation errors for the address.
-func NewValid(fields ...func(*Address)) (Address, error) {
+// transliterate transliterates the free-text fields using the Transliterator when the address is latinized.
+// The country is not transliterated, since its name is already in the language used for formatting.
+func (f formatData) transliterate(latinize bool, transliterator Transliterator) formatData {
 
-	address := New(fields...)
+	if !latinize || transliterator == nil {
+		return f
+	}
 
-	err := Validate(address)
+	streetAddress := make([]string, len(f.StreetAddress))
 
-	if err != nil {
-		return address, fmt.Errorf("invalid address: %w", err)
+	for i, line := range f.StreetAddress {
+		streetAddress[i] = transliterator.Transliterate(line)
 	}
 
-	return address, nil
+	f.StreetAddress = streetAddress
+	f.Name = transliterator.Transliterate(f.Name)
+	f.Organization = transliterator.Transliterate(f.Organization)
+	f.DependentLocality = transliterator.Transliterate(f.DependentLocality)
+	f.Locality = transliterator.Transliterate(f.Locality)
+	f.AdministrativeArea = transliterator.Transliterate(f.AdministrativeArea)
+	f.SortingCode = transliterator.Transliterate(f.SortingCode)
+
+	return f
+}
+
+// NewValid creates a new Address. If the address is invalid, an error is returned.
+// In the case where an error is returned, the error is a hashicorp/go-multierror (https://github.com/hashicorp/go-multierror).
+// You can use a type switch to get a list of validation errors for the address.
+func NewValid(fields ...func(*Address)) (Address, error) {
+	return defaultRegistry.NewValid(fields...)
 }
 
 // New creates a new unvalidated address. The validity of the address should be checked
@@ -207,51 +218,111 @@ func WithSortingCode(sortingCode string) func(*Address) {
 // The AdministrativeAreas field contains a list of nested subdivisions (administrative areas, localities and dependent
 // localities) grouped by their translated languages. They are also sorted according to the sort order of the languages
 // they are in.
+// PostCodePrefix contains the prefix that is printed before post codes in the country, if any. It includes the
+// separator between the prefix and the post code (for example, "PR " for Puerto Rico), so it can be prepended to the
+// post code as is. PostURL contains the URL of the post office's post code lookup, if any.
+// AdministrativeAreaRedirects contains the administrative areas that are also separate countries, which are not in
+// AdministrativeAreas.
 type CountryData struct {
-	Format                     string
-	LatinizedFormat            string
-	Required                   []Field
-	Allowed                    []Field
-	DefaultLanguage            string
-	AdministrativeAreaNameType FieldName
-	LocalityNameType           FieldName
-	DependentLocalityNameType  FieldName
-	PostCodeNameType           FieldName
-	PostCodeRegex              PostCodeRegexData
-	AdministrativeAreas        map[string][]AdministrativeAreaData
+	Format                      string
+	LatinizedFormat             string
+	Required                    []Field
+	Allowed                     []Field
+	DefaultLanguage             string
+	AdministrativeAreaNameType  FieldName
+	LocalityNameType            FieldName
+	DependentLocalityNameType   FieldName
+	PostCodeNameType            FieldName
+	PostCodePrefix              string
+	PostCodeRegex               PostCodeRegexData
+	PostURL                     string
+	AdministrativeAreas         map[string][]AdministrativeAreaData
+	AdministrativeAreaRedirects []AdministrativeAreaRedirect
 }
 
 // PostCodeRegexData contains regular expressions for validating post codes for a given country.
 // If the country has subdivisions (administrative areas, localities and dependent localities), the SubdivisionRegex
 // field may contain further regular expressions to Validate the post code.
+// Examples contains sample post codes from Google's Address Data Service that match the regular expression, which are
+// useful as placeholders in forms.
 type PostCodeRegexData struct {
 	Regex            string
+	Examples         []string
 	SubdivisionRegex map[string]PostCodeRegexData
 }
 
+// PostCodeExamples returns sample post codes for the country. If the IDs of an administrative area, locality and
+// dependent locality are passed, in that order, it returns the examples of the most specific of those subdivisions
+// that has examples, falling back to the examples for the country.
+func (c CountryData) PostCodeExamples(subdivisionIDs ...string) []string {
+
+	examples := c.PostCodeRegex.Examples
+	regex := c.PostCodeRegex
+
+	for _, id := range subdivisionIDs {
+
+		subdivisionRegex, ok := regex.SubdivisionRegex[id]
+
+		if !ok {
+			break
+		}
+
+		if len(subdivisionRegex.Examples) > 0 {
+			examples = subdivisionRegex.Examples
+		}
+
+		regex = subdivisionRegex
+	}
+
+	return examples
+}
+
 // AdministrativeAreaData contains the name and ID of and administrative area. The ID must be passed to
 // WithAdministrativeArea() when creating an address. The name is useful for displaying to the end user.
+// PostalKey contains the key of the administrative area in Google's data, which is the form used by the postal service
+// (for example, NSW for New South Wales). ISOID contains the subdivision part of the administrative area's ISO 3166-2
+// code (for example, NSW for AU-NSW), if it has one. LatinizedName contains the name in the Latin script, if the data
+// contains one.
 type AdministrativeAreaData struct {
-	ID   string
-	Name string
+	ID            string
+	Name          string
+	PostalKey     string
+	ISOID         string
+	LatinizedName string
 
 	Localities []LocalityData
 }
 
 // LocalityData contains the name and ID of and administrative area. The ID must be passed to
 // WithLocalityData() when creating an address. The name is useful for displaying to the end user.
+// LatinizedName contains the name in the Latin script, if the data contains one.
 type LocalityData struct {
-	ID   string
-	Name string
+	ID            string
+	Name          string
+	LatinizedName string
 
 	DependentLocalities []DependentLocalityData
 }
 
 // DependentLocalityData contains the name and ID of and administrative area. The ID must be passed to
 // WithDependentLocalityData() when creating an address. The name is useful for displaying to the end user.
+// LatinizedName contains the name in the Latin script, if the data contains one.
 type DependentLocalityData struct {
-	ID   string
-	Name string
+	ID            string
+	Name          string
+	LatinizedName string
+}
+
+// AdministrativeAreaRedirect is an administrative area in Google's data that is also a separate country, such as Puerto
+// Rico in the US or Hong Kong in China. Addresses in the administrative area must use the country code of the
+// separate country, which is in CountryCode. The ID, Name, PostalKey and LatinizedName are the same as they would be
+// in AdministrativeAreaData.
+type AdministrativeAreaRedirect struct {
+	ID            string
+	Name          string
+	PostalKey     string
+	LatinizedName string
+	CountryCode   string
 }
 
 // CountryList contains a list of countries that can be used to create addresses.
@@ -286,47 +357,13 @@ type CountryListItem struct {
 // If the language does not have any translations or is invalid, then English is used as the fallback language.
 // The returned list of countries is sorted according to the chosing language.
 func ListCountries(language string) []CountryListItem {
-
-	l, err := textLanguage.Parse(language)
-
-	if err != nil {
-		l = textLanguage.English
-	}
-
-	c := collate.New(l)
-	namer := display.Regions(l)
-
-	if namer == nil {
-		namer = display.Regions(textLanguage.English)
-	}
-
-	var countries CountryList
-
-	for countryCode := range generated {
-
-		if countryCode == "ZZ" {
-			continue
-		}
-
-		country := textLanguage.MustParseRegion(countryCode)
-
-		countries = append(countries, CountryListItem{
-			Code: countryCode,
-			Name: namer.Name(country),
-		})
-	}
-
-	c.Sort(countries)
-
-	return countries
+	return defaultRegistry.ListCountries(language)
 }
 
 // GetCountry returns address information for a given country.
 func GetCountry(countryCode string) CountryData {
 
-	country := generated.getCountry(countryCode)
-
-	return internalCountryDataToCountryData(country)
+	return defaultRegistry.GetCountry(countryCode)
 }
 
 func internalCountryDataToCountryData(country country) CountryData {
@@ -339,7 +376,9 @@ func internalCountryDataToCountryData(country country) CountryData {
 		LocalityNameType:           country.LocalityNameType,
 		DependentLocalityNameType:  country.DependentLocalityNameType,
 		PostCodeNameType:           country.PostCodeNameType,
+		PostCodePrefix:             country.PostCodePrefix,
 		PostCodeRegex:              internalPostCodeRegexToPostCodeRegexData(country.PostCodeRegex),
+		PostURL:                    country.PostURL,
 	}
 
 	var required []Field
@@ -376,16 +415,116 @@ func internalCountryDataToCountryData(country country) CountryData {
 		data.AdministrativeAreas = administrativeAreas
 	}
 
+	for _, redirect := range country.AdministrativeAreaRedirects {
+		data.AdministrativeAreaRedirects = append(data.AdministrativeAreaRedirects, AdministrativeAreaRedirect(redirect))
+	}
+
 	return data
 }
 
+func countryDataToInternalCountryData(countryCode, name string, countryData CountryData) country {
+
+	data := country{
+		ID:                         countryCode,
+		Name:                       name,
+		DefaultLanguage:            countryData.DefaultLanguage,
+		PostCodePrefix:             countryData.PostCodePrefix,
+		PostCodeRegex:              postCodeRegexDataToInternalPostCodeRegex(countryData.PostCodeRegex),
+		PostURL:                    countryData.PostURL,
+		Format:                     countryData.Format,
+		LatinizedFormat:            countryData.LatinizedFormat,
+		AdministrativeAreaNameType: countryData.AdministrativeAreaNameType,
+		LocalityNameType:           countryData.LocalityNameType,
+		DependentLocalityNameType:  countryData.DependentLocalityNameType,
+		PostCodeNameType:           countryData.PostCodeNameType,
+		AllowedFields:              fieldSet(countryData.Allowed),
+		RequiredFields:             fieldSet(countryData.Required),
+	}
+
+	if len(countryData.AdministrativeAreas) > 0 {
+
+		data.AdministrativeAreas = map[string][]administrativeArea{}
+
+		for lang, adminAreas := range countryData.AdministrativeAreas {
+			data.AdministrativeAreas[lang] = administrativeAreaDataToInternalAdministrativeAreas(adminAreas)
+		}
+	}
+
+	for _, redirect := range countryData.AdministrativeAreaRedirects {
+		data.AdministrativeAreaRedirects = append(data.AdministrativeAreaRedirects, administrativeAreaRedirect(redirect))
+	}
+
+	return data
+}
+
+func postCodeRegexDataToInternalPostCodeRegex(regex PostCodeRegexData) postCodeRegex {
+
+	result := postCodeRegex{
+		Regex:    regex.Regex,
+		Examples: slices.Clone(regex.Examples),
+	}
+
+	for subID, regex := range regex.SubdivisionRegex {
+
+		if result.SubdivisionRegex == nil {
+			result.SubdivisionRegex = map[string]postCodeRegex{}
+		}
+
+		result.SubdivisionRegex[subID] = postCodeRegexDataToInternalPostCodeRegex(regex)
+	}
+
+	return result
+}
+
+func administrativeAreaDataToInternalAdministrativeAreas(areas []AdministrativeAreaData) []administrativeArea {
+
+	var result []administrativeArea
+
+	for _, adminArea := range areas {
+
+		var localities []locality
+
+		for _, localityData := range adminArea.Localities {
+
+			var dependentLocalities []dependentLocality
+
+			for _, dependentLocalityData := range localityData.DependentLocalities {
+				dependentLocalities = append(dependentLocalities, dependentLocality{
+					ID:            dependentLocalityData.ID,
+					Name:          dependentLocalityData.Name,
+					LatinizedName: dependentLocalityData.LatinizedName,
+				})
+			}
+
+			localities = append(localities, locality{
+				ID:                  localityData.ID,
+				Name:                localityData.Name,
+				LatinizedName:       localityData.LatinizedName,
+				DependentLocalities: dependentLocalities,
+			})
+		}
+
+		result = append(result, administrativeArea{
+			ID:            adminArea.ID,
+			Name:          adminArea.Name,
+			PostalKey:     adminArea.PostalKey,
+			ISOID:         adminArea.ISOID,
+			LatinizedName: adminArea.LatinizedName,
+			Localities:    localities,
+		})
+	}
+
+	return result
+}
+
 func internalPostCodeRegexToPostCodeRegexData(regex postCodeRegex) PostCodeRegexData {
 
 	result := PostCodeRegexData{
-		Regex: regex.regex,
+		Regex:    regex.Regex,
+		Examples: slices.Clone(regex.Examples),
 	}
 
-	for subID, regex := range regex.subdivisionRegex {
+	for subID, regex := range regex.SubdivisionRegex {
 
 		if result.SubdivisionRegex == nil {
 			result.SubdivisionRegex = map[string]PostCodeRegexData{}
@@ -411,14 +550,16 @@ func internalAdministrativeAreasToAdministrativeAreaData(areas []administrativeA
 
 			for _, dependentLocality := range locality.DependentLocalities {
 				dependentLocalities = append(dependentLocalities, DependentLocalityData{
-					ID:   dependentLocality.ID,
-					Name: dependentLocality.Name,
+					ID:            dependentLocality.ID,
+					Name:          dependentLocality.Name,
+					LatinizedName: dependentLocality.LatinizedName,
 				})
 			}
 
 			localityData := LocalityData{
-				ID:   locality.ID,
-				Name: locality.Name,
+				ID:            locality.ID,
+				Name:          locality.Name,
+				LatinizedName: locality.LatinizedName,
 			}
 
 			if len(dependentLocalities) > 0 {
@@ -429,8 +570,11 @@ func internalAdministrativeAreasToAdministrativeAreaData(areas []administrativeA
 		}
 
 		adminAreaData := AdministrativeAreaData{
-			ID:   adminArea.ID,
-			Name: adminArea.Name,
+			ID:            adminArea.ID,
+			Name:          adminArea.Name,
+			PostalKey:     adminArea.PostalKey,
+			ISOID:         adminArea.ISOID,
+			LatinizedName: adminArea.LatinizedName,
 		}
 
 		if len(localities) > 0 {
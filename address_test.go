@@ -3,6 +3,8 @@ package address
 import (
 	"errors"
 	"reflect"
+	"regexp"
+	"slices"
 	"testing"
 )
 
@@ -414,7 +416,7 @@ func TestGetCountries(t *testing.T) {
 	countries := ListCountries("en")
 
 	numCountries := len(countries)
-	expected := len(generated) - 1
+	expected := len(generated.countryCodes()) - 1
 
 	if numCountries != expected {
 		t.Errorf("Number of countries (%d) does not equal expected number of countries (%d)", numCountries, expected)
@@ -433,7 +435,7 @@ func TestGetCountries(t *testing.T) {
 	countries = ListCountries("zh")
 
 	numCountries = len(countries)
-	expected = len(generated) - 1
+	expected = len(generated.countryCodes()) - 1
 
 	if numCountries != expected {
 		t.Errorf("Number of countries (%d) does not equal expected number of countries (%d)", numCountries, expected)
@@ -468,59 +470,85 @@ func TestGetCountry(t *testing.T) {
 		DependentLocalityNameType:  Suburb,
 		PostCodeNameType:           PostalCode,
 		PostCodeRegex: PostCodeRegexData{
-			Regex: `^(\d{4})$`,
+			Regex:    `^(\d{4})$`,
+			Examples: []string{"2060", "3171", "6430", "4000", "4006", "3001"},
 			SubdivisionRegex: map[string]PostCodeRegexData{
 				"ACT": {
-					Regex: `^29|2540|260|261[0-8]|02|2620`},
+					Regex:    `^29|2540|260|261[0-8]|02|2620`,
+					Examples: []string{"0200", "2540", "2618", "2999"}},
 				"NSW": {
-					Regex: `^1|2[0-57-8]|26[2-9]|261[189]|3500|358[56]|3644|3707`},
+					Regex:    `^1|2[0-57-8]|26[2-9]|261[189]|3500|358[56]|3644|3707`,
+					Examples: []string{"1100", "2000", "2520", "2640", "2700", "3500", "3585", "3586", "3644", "3707"}},
 				"NT": {
-					Regex: `^0[89]`},
+					Regex:    `^0[89]`,
+					Examples: []string{"0800", "0999"}},
 				"QLD": {
-					Regex: `^[49]`},
+					Regex:    `^[49]`,
+					Examples: []string{"4000", "9999"}},
 				"SA": {
-					Regex: `^5|0872`},
+					Regex:    `^5|0872`,
+					Examples: []string{"5000", "5799", "0872"}},
 				"TAS": {
-					Regex: `^7`},
+					Regex:    `^7`,
+					Examples: []string{"7000", "7999"}},
 				"VIC": {
-					Regex: `^[38]`},
+					Regex:    `^[38]`,
+					Examples: []string{"3000", "8000"}},
 				"WA": {
-					Regex: `^6|0872`},
+					Regex:    `^6|0872`,
+					Examples: []string{"6000", "0872"}},
 			},
 		},
+		PostURL: "http://www1.auspost.com.au/postcodes/",
 		AdministrativeAreas: map[string][]AdministrativeAreaData{
 			"en": {
 				{
-					ID:   "ACT",
-					Name: "Australian Capital Territory",
+					ID:        "ACT",
+					Name:      "Australian Capital Territory",
+					PostalKey: "ACT",
+					ISOID:     "ACT",
 				},
 				{
-					ID:   "NSW",
-					Name: "New South Wales",
+					ID:        "NSW",
+					Name:      "New South Wales",
+					PostalKey: "NSW",
+					ISOID:     "NSW",
 				},
 				{
-					ID:   "NT",
-					Name: "Northern Territory",
+					ID:        "NT",
+					Name:      "Northern Territory",
+					PostalKey: "NT",
+					ISOID:     "NT",
 				},
 				{
-					ID:   "QLD",
-					Name: "Queensland",
+					ID:        "QLD",
+					Name:      "Queensland",
+					PostalKey: "QLD",
+					ISOID:     "QLD",
 				},
 				{
-					ID:   "SA",
-					Name: "South Australia",
+					ID:        "SA",
+					Name:      "South Australia",
+					PostalKey: "SA",
+					ISOID:     "SA",
 				},
 				{
-					ID:   "TAS",
-					Name: "Tasmania",
+					ID:        "TAS",
+					Name:      "Tasmania",
+					PostalKey: "TAS",
+					ISOID:     "TAS",
 				},
 				{
-					ID:   "VIC",
-					Name: "Victoria",
+					ID:        "VIC",
+					Name:      "Victoria",
+					PostalKey: "VIC",
+					ISOID:     "VIC",
 				},
 				{
-					ID:   "WA",
-					Name: "Western Australia",
+					ID:        "WA",
+					Name:      "Western Australia",
+					PostalKey: "WA",
+					ISOID:     "WA",
 				},
 			},
 		},
@@ -1194,8 +1222,9 @@ func TestGetCountry(t *testing.T) {
 		AdministrativeAreas: map[string][]AdministrativeAreaData{
 			"en": {
 				{
-					ID:   "26",
-					Name: "Busan",
+					ID:        "26",
+					Name:      "Busan",
+					PostalKey: "부산광역시",
 					Localities: []LocalityData{
 						{
 							ID:   "북구",
@@ -1264,8 +1293,9 @@ func TestGetCountry(t *testing.T) {
 					},
 				},
 				{
-					ID:   "43",
-					Name: "Chungcheongbuk-do",
+					ID:        "43",
+					Name:      "Chungcheongbuk-do",
+					PostalKey: "충청북도",
 					Localities: []LocalityData{
 						{
 							ID:   "보은군",
@@ -1332,8 +1362,9 @@ func TestGetCountry(t *testing.T) {
 					},
 				},
 				{
-					ID:   "44",
-					Name: "Chungcheongnam-do",
+					ID:        "44",
+					Name:      "Chungcheongnam-do",
+					PostalKey: "충청남도",
 					Localities: []LocalityData{
 						{
 							ID:   "아산시",
@@ -1408,8 +1439,9 @@ func TestGetCountry(t *testing.T) {
 					},
 				},
 				{
-					ID:   "27",
-					Name: "Daegu",
+					ID:        "27",
+					Name:      "Daegu",
+					PostalKey: "대구광역시",
 					Localities: []LocalityData{
 						{
 							ID:   "북구",
@@ -1446,8 +1478,9 @@ func TestGetCountry(t *testing.T) {
 					},
 				},
 				{
-					ID:   "30",
-					Name: "Daejeon",
+					ID:        "30",
+					Name:      "Daejeon",
+					PostalKey: "대전광역시",
 					Localities: []LocalityData{
 						{
 							ID:   "대덕구",
@@ -1472,8 +1505,9 @@ func TestGetCountry(t *testing.T) {
 					},
 				},
 				{
-					ID:   "42",
-					Name: "Gangwon-do",
+					ID:        "42",
+					Name:      "Gangwon-do",
+					PostalKey: "강원도",
 					Localities: []LocalityData{
 						{
 							ID:   "철원군",
@@ -1550,8 +1584,9 @@ func TestGetCountry(t *testing.T) {
 					},
 				},
 				{
-					ID:   "29",
-					Name: "Gwangju",
+					ID:        "29",
+					Name:      "Gwangju",
+					PostalKey: "광주광역시",
 					Localities: []LocalityData{
 						{
 							ID:   "북구",
@@ -1576,8 +1611,9 @@ func TestGetCountry(t *testing.T) {
 					},
 				},
 				{
-					ID:   "41",
-					Name: "Gyeonggi-do",
+					ID:        "41",
+					Name:      "Gyeonggi-do",
+					PostalKey: "경기도",
 					Localities: []LocalityData{
 						{
 							ID:   "안산시",
@@ -1800,8 +1836,9 @@ func TestGetCountry(t *testing.T) {
 					},
 				},
 				{
-					ID:   "47",
-					Name: "Gyeongsangbuk-do",
+					ID:        "47",
+					Name:      "Gyeongsangbuk-do",
+					PostalKey: "경상북도",
 					Localities: []LocalityData{
 						{
 							ID:   "안동시",
@@ -1908,8 +1945,9 @@ func TestGetCountry(t *testing.T) {
 					},
 				},
 				{
-					ID:   "48",
-					Name: "Gyeongsangnam-do",
+					ID:        "48",
+					Name:      "Gyeongsangnam-do",
+					PostalKey: "경상남도",
 					Localities: []LocalityData{
 						{
 							ID:   "창녕군",
@@ -2008,8 +2046,9 @@ func TestGetCountry(t *testing.T) {
 					},
 				},
 				{
-					ID:   "28",
-					Name: "Incheon",
+					ID:        "28",
+					Name:      "Incheon",
+					PostalKey: "인천광역시",
 					Localities: []LocalityData{
 						{
 							ID:   "부평구",
@@ -2054,8 +2093,9 @@ func TestGetCountry(t *testing.T) {
 					},
 				},
 				{
-					ID:   "49",
-					Name: "Jeju-do",
+					ID:        "49",
+					Name:      "Jeju-do",
+					PostalKey: "제주특별자치도",
 					Localities: []LocalityData{
 						{
 							ID:   "제주시",
@@ -2068,8 +2108,9 @@ func TestGetCountry(t *testing.T) {
 					},
 				},
 				{
-					ID:   "45",
-					Name: "Jeollabuk-do",
+					ID:        "45",
+					Name:      "Jeollabuk-do",
+					PostalKey: "전라북도",
 					Localities: []LocalityData{
 						{
 							ID:   "부안군",
@@ -2140,8 +2181,9 @@ func TestGetCountry(t *testing.T) {
 					},
 				},
 				{
-					ID:   "46",
-					Name: "Jeollanam-do",
+					ID:        "46",
+					Name:      "Jeollanam-do",
+					PostalKey: "전라남도",
 					Localities: []LocalityData{
 						{
 							ID:   "보성군",
@@ -2234,8 +2276,9 @@ func TestGetCountry(t *testing.T) {
 					},
 				},
 				{
-					ID:   "50",
-					Name: "Sejong",
+					ID:        "50",
+					Name:      "Sejong",
+					PostalKey: "세종특별자치시",
 					Localities: []LocalityData{
 						{
 							ID:   "아름동",
@@ -2320,8 +2363,9 @@ func TestGetCountry(t *testing.T) {
 					},
 				},
 				{
-					ID:   "11",
-					Name: "Seoul",
+					ID:        "11",
+					Name:      "Seoul",
+					PostalKey: "서울특별시",
 					Localities: []LocalityData{
 						{
 							ID:   "도봉구",
@@ -2426,8 +2470,9 @@ func TestGetCountry(t *testing.T) {
 					},
 				},
 				{
-					ID:   "31",
-					Name: "Ulsan",
+					ID:        "31",
+					Name:      "Ulsan",
+					PostalKey: "울산광역시",
 					Localities: []LocalityData{
 						{
 							ID:   "북구",
@@ -2454,8 +2499,9 @@ func TestGetCountry(t *testing.T) {
 			},
 			"ko": {
 				{
-					ID:   "42",
-					Name: "강원",
+					ID:        "42",
+					Name:      "강원",
+					PostalKey: "강원도",
 					Localities: []LocalityData{
 						{
 							ID:   "강릉시",
@@ -2532,8 +2578,9 @@ func TestGetCountry(t *testing.T) {
 					},
 				},
 				{
-					ID:   "41",
-					Name: "경기",
+					ID:        "41",
+					Name:      "경기",
+					PostalKey: "경기도",
 					Localities: []LocalityData{
 						{
 							ID:   "가평군",
@@ -2756,8 +2803,9 @@ func TestGetCountry(t *testing.T) {
 					},
 				},
 				{
-					ID:   "48",
-					Name: "경남",
+					ID:        "48",
+					Name:      "경남",
+					PostalKey: "경상남도",
 					Localities: []LocalityData{
 						{
 							ID:   "거제시",
@@ -2856,8 +2904,9 @@ func TestGetCountry(t *testing.T) {
 					},
 				},
 				{
-					ID:   "47",
-					Name: "경북",
+					ID:        "47",
+					Name:      "경북",
+					PostalKey: "경상북도",
 					Localities: []LocalityData{
 						{
 							ID:   "경산시",
@@ -2964,8 +3013,9 @@ func TestGetCountry(t *testing.T) {
 					},
 				},
 				{
-					ID:   "29",
-					Name: "광주",
+					ID:        "29",
+					Name:      "광주",
+					PostalKey: "광주광역시",
 					Localities: []LocalityData{
 						{
 							ID:   "광산구",
@@ -2990,8 +3040,9 @@ func TestGetCountry(t *testing.T) {
 					},
 				},
 				{
-					ID:   "27",
-					Name: "대구",
+					ID:        "27",
+					Name:      "대구",
+					PostalKey: "대구광역시",
 					Localities: []LocalityData{
 						{
 							ID:   "남구",
@@ -3028,8 +3079,9 @@ func TestGetCountry(t *testing.T) {
 					},
 				},
 				{
-					ID:   "30",
-					Name: "대전",
+					ID:        "30",
+					Name:      "대전",
+					PostalKey: "대전광역시",
 					Localities: []LocalityData{
 						{
 							ID:   "대덕구",
@@ -3054,8 +3106,9 @@ func TestGetCountry(t *testing.T) {
 					},
 				},
 				{
-					ID:   "26",
-					Name: "부산",
+					ID:        "26",
+					Name:      "부산",
+					PostalKey: "부산광역시",
 					Localities: []LocalityData{
 						{
 							ID:   "강서구",
@@ -3124,8 +3177,9 @@ func TestGetCountry(t *testing.T) {
 					},
 				},
 				{
-					ID:   "11",
-					Name: "서울",
+					ID:        "11",
+					Name:      "서울",
+					PostalKey: "서울특별시",
 					Localities: []LocalityData{
 						{
 							ID:   "강남구",
@@ -3230,8 +3284,9 @@ func TestGetCountry(t *testing.T) {
 					},
 				},
 				{
-					ID:   "50",
-					Name: "세종",
+					ID:        "50",
+					Name:      "세종",
+					PostalKey: "세종특별자치시",
 					Localities: []LocalityData{
 						{
 							ID:   "고운동",
@@ -3316,8 +3371,9 @@ func TestGetCountry(t *testing.T) {
 					},
 				},
 				{
-					ID:   "31",
-					Name: "울산",
+					ID:        "31",
+					Name:      "울산",
+					PostalKey: "울산광역시",
 					Localities: []LocalityData{
 						{
 							ID:   "남구",
@@ -3342,8 +3398,9 @@ func TestGetCountry(t *testing.T) {
 					},
 				},
 				{
-					ID:   "28",
-					Name: "인천",
+					ID:        "28",
+					Name:      "인천",
+					PostalKey: "인천광역시",
 					Localities: []LocalityData{
 						{
 							ID:   "강화군",
@@ -3388,8 +3445,9 @@ func TestGetCountry(t *testing.T) {
 					},
 				},
 				{
-					ID:   "46",
-					Name: "전남",
+					ID:        "46",
+					Name:      "전남",
+					PostalKey: "전라남도",
 					Localities: []LocalityData{
 						{
 							ID:   "강진군",
@@ -3482,8 +3540,9 @@ func TestGetCountry(t *testing.T) {
 					},
 				},
 				{
-					ID:   "45",
-					Name: "전북",
+					ID:        "45",
+					Name:      "전북",
+					PostalKey: "전라북도",
 					Localities: []LocalityData{
 						{
 							ID:   "고창군",
@@ -3554,8 +3613,9 @@ func TestGetCountry(t *testing.T) {
 					},
 				},
 				{
-					ID:   "49",
-					Name: "제주",
+					ID:        "49",
+					Name:      "제주",
+					PostalKey: "제주특별자치도",
 					Localities: []LocalityData{
 						{
 							ID:   "서귀포시",
@@ -3568,8 +3628,9 @@ func TestGetCountry(t *testing.T) {
 					},
 				},
 				{
-					ID:   "44",
-					Name: "충남",
+					ID:        "44",
+					Name:      "충남",
+					PostalKey: "충청남도",
 					Localities: []LocalityData{
 						{
 							ID:   "계룡시",
@@ -3644,8 +3705,9 @@ func TestGetCountry(t *testing.T) {
 					},
 				},
 				{
-					ID:   "43",
-					Name: "충북",
+					ID:        "43",
+					Name:      "충북",
+					PostalKey: "충청북도",
 					Localities: []LocalityData{
 						{
 							ID:   "괴산군",
@@ -3742,3 +3804,75 @@ func TestValidErr(t *testing.T) {
 		}
 	}
 }
+
+func TestCountryDataPostCodeExamples(t *testing.T) {
+
+	country := GetCountry("AU")
+
+	testCases := []struct {
+		SubdivisionIDs []string
+		Expected       []string
+	}{
+		{
+			Expected: []string{"2060", "3171", "6430", "4000", "4006", "3001"},
+		},
+		{
+			SubdivisionIDs: []string{"VIC"},
+			Expected:       []string{"3000", "8000"},
+		},
+		{
+			SubdivisionIDs: []string{"VIC", "Melbourne"},
+			Expected:       []string{"3000", "8000"},
+		},
+		{
+			SubdivisionIDs: []string{"XX"},
+			Expected:       []string{"2060", "3171", "6430", "4000", "4006", "3001"},
+		},
+	}
+
+	for i, testCase := range testCases {
+		if examples := country.PostCodeExamples(testCase.SubdivisionIDs...); !reflect.DeepEqual(examples, testCase.Expected) {
+			t.Errorf("Expected examples %v for test case %d, got %v", testCase.Expected, i, examples)
+		}
+	}
+}
+
+// TestPostCodeExamplesMatchRegex checks that the examples of every country and subdivision are valid post codes.
+func TestPostCodeExamplesMatchRegex(t *testing.T) {
+
+	var check func(countryCode string, path []string, regex PostCodeRegexData)
+
+	check = func(countryCode string, path []string, regex PostCodeRegexData) {
+
+		if len(regex.Examples) > 0 {
+
+			compiled := regexp.MustCompile(regex.Regex)
+
+			for _, example := range regex.Examples {
+				if !compiled.MatchString(example) {
+					t.Errorf("Example %s of %s %v does not match %s", example, countryCode, path, regex.Regex)
+				}
+			}
+		}
+
+		for id, subdivisionRegex := range regex.SubdivisionRegex {
+			check(countryCode, append(slices.Clone(path), id), subdivisionRegex)
+		}
+	}
+
+	for _, country := range ListCountries("en") {
+
+		data := GetCountry(country.Code)
+
+		check(country.Code, nil, data.PostCodeRegex)
+
+		for _, example := range data.PostCodeExamples() {
+
+			address := New(WithCountry(country.Code), WithPostCode(example))
+
+			if err := Validate(address); errors.Is(err, ErrInvalidPostCode) {
+				t.Errorf("Example %s of %s is not a valid post code: %s", example, country.Code, err)
+			}
+		}
+	}
+}
@@ -1,5 +1,15 @@
 package address
 
+import (
+	"maps"
+	"sort"
+	"strings"
+
+	"github.com/Boostport/address/internal/addressdata"
+	textLanguage "golang.org/x/text/language"
+	"golang.org/x/text/language/display"
+)
+
 type country struct {
 	ID   string
 	Name string
@@ -8,6 +18,7 @@ type country struct {
 
 	PostCodePrefix string
 	PostCodeRegex  postCodeRegex
+	PostURL        string
 
 	Format          string
 	LatinizedFormat string
@@ -21,39 +32,156 @@ type country struct {
 	RequiredFields map[Field]struct{}
 	Upper          map[Field]struct{}
 
-	AdministrativeAreas map[string][]administrativeArea
+	AdministrativeAreas         map[string][]administrativeArea
+	AdministrativeAreaRedirects []administrativeAreaRedirect
 }
 
-type postCodeRegex struct {
-	regex            string
-	subdivisionRegex map[string]postCodeRegex
+type postCodeRegex = addressdata.PostCodeRegex
+
+type administrativeArea = addressdata.AdministrativeArea
+
+type locality = addressdata.Locality
+
+type dependentLocality = addressdata.DependentLocality
+
+type administrativeAreaRedirect = addressdata.AdministrativeAreaRedirect
+
+// newCountry converts a country in the address data into a country using the field types of the package.
+func newCountry(c addressdata.Country) country {
+	return country{
+		ID:   c.ID,
+		Name: c.Name,
+
+		DefaultLanguage: c.DefaultLanguage,
+
+		PostCodePrefix: c.PostCodePrefix,
+		PostCodeRegex:  c.PostCodeRegex,
+		PostURL:        c.PostURL,
+
+		Format:          c.Format,
+		LatinizedFormat: c.LatinizedFormat,
+
+		AdministrativeAreaNameType: FieldName(c.AdministrativeAreaNameType),
+		LocalityNameType:           FieldName(c.LocalityNameType),
+		DependentLocalityNameType:  FieldName(c.DependentLocalityNameType),
+		PostCodeNameType:           FieldName(c.PostCodeNameType),
+
+		AllowedFields:  decodedFieldSet(c.AllowedFields),
+		RequiredFields: decodedFieldSet(c.RequiredFields),
+		Upper:          decodedFieldSet(c.Upper),
+
+		AdministrativeAreas:         c.AdministrativeAreas,
+		AdministrativeAreaRedirects: c.AdministrativeAreaRedirects,
+	}
 }
 
-type administrativeArea struct {
-	ID        string
-	Name      string
-	PostalKey string
+// decodedFieldSet returns the fields in a set of fields in the address data, or nil if the set is empty.
+func decodedFieldSet(fields addressdata.FieldSet) map[Field]struct{} {
+
+	if fields == 0 {
+		return nil
+	}
+
+	set := map[Field]struct{}{}
+
+	for field := Country; field <= SortingCode; field++ {
+		if fields&(1<<field) != 0 {
+			set[field] = struct{}{}
+		}
+	}
+
+	return set
+}
 
-	Localities []locality
+// data holds the address data of a registry. The countries in the countries map take precedence over the encoded
+// countries, which are decoded the first time they are used.
+type data struct {
+	countries map[string]country
+	encoded   *encodedData
 }
 
-type locality struct {
-	ID   string
-	Name string
+func (d data) country(countryCode string) (country, bool) {
 
-	DependentLocalities []dependentLocality
+	if c, ok := d.countries[countryCode]; ok {
+		return c, true
+	}
+
+	if d.encoded != nil {
+		return d.encoded.country(countryCode)
+	}
+
+	return country{}, false
 }
 
-type dependentLocality struct {
-	ID   string
-	Name string
+// withCountry returns a copy of the data with a country added or replaced, leaving the original data unmodified.
+func (d data) withCountry(countryCode string, c country) data {
+
+	countries := maps.Clone(d.countries)
+
+	if countries == nil {
+		countries = map[string]country{}
+	}
+
+	countries[countryCode] = c
+
+	return data{
+		countries: countries,
+		encoded:   d.encoded,
+	}
 }
 
-type data map[string]country
+// countryCodes returns the codes of all the countries in the data, including ZZ, in order.
+func (d data) countryCodes() []string {
+
+	var codes []string
+
+	if d.encoded != nil {
+		codes = d.encoded.countryCodes()
+	}
+
+	for code := range d.countries {
+		if d.encoded == nil || !d.encoded.hasCountry(code) {
+			codes = append(codes, code)
+		}
+	}
+
+	sort.Strings(codes)
+
+	return codes
+}
+
+// name returns the name of a country in the data. Unlike getCountry, it does not decode the country.
+func (d data) name(countryCode string) string {
+
+	if c, ok := d.countries[countryCode]; ok {
+		return c.Name
+	}
+
+	if d.encoded != nil {
+		return d.encoded.index[countryCode].Name
+	}
+
+	return ""
+}
+
+// defaultLanguage returns the default language of a country in the data. Unlike getCountry, it does not decode the
+// country.
+func (d data) defaultLanguage(countryCode string) string {
+
+	if c, ok := d.countries[countryCode]; ok {
+		return c.DefaultLanguage
+	}
+
+	if d.encoded != nil {
+		return d.encoded.index[countryCode].DefaultLanguage
+	}
+
+	return ""
+}
 
 func (d data) getCountry(countryCode string) country {
-	data := generated[countryCode]
-	defaults := generated["ZZ"]
+	data, _ := d.country(countryCode)
+	defaults, _ := d.country("ZZ")
 
 	if data.Format == "" {
 		data.Format = defaults.Format
@@ -91,9 +219,12 @@ func (d data) getCountry(countryCode string) country {
 }
 
 func (d data) hasCountry(countryCode string) bool {
-	_, ok := generated[countryCode]
 
-	return ok
+	if _, ok := d.countries[countryCode]; ok {
+		return true
+	}
+
+	return d.encoded != nil && d.encoded.hasCountry(countryCode)
 }
 
 func (d data) getAdministrativeAreaName(countryCode, administrativeAreaID, language string) string {
@@ -178,3 +309,169 @@ func (d data) normalizeLanguage(countryCode, language string) string {
 
 	return country.DefaultLanguage
 }
+
+// latinizedLanguage returns the language to use for the names of subdivisions when formatting an address in the latin
+// alphabet. The Latin script variant of the language (for example, ja-Latn) is preferred, followed by English. If the
+// country has neither, the normalized language is returned.
+func (d data) latinizedLanguage(countryCode, language string) string {
+
+	country := d.getCountry(countryCode)
+
+	for _, candidate := range []string{language + "-Latn", "en"} {
+		if _, ok := country.AdministrativeAreas[candidate]; ok {
+			return candidate
+		}
+	}
+
+	return d.normalizeLanguage(countryCode, language)
+}
+
+// getAdministrativeAreaID returns the ID of an administrative area given its ID, name or postal key in any of the
+// languages available for the country. If the administrative area cannot be found, an empty string is returned.
+func (d data) getAdministrativeAreaID(countryCode, administrativeArea string) string {
+
+	data := d.getCountry(countryCode)
+
+	for _, adminAreas := range data.AdministrativeAreas {
+		for _, adminArea := range adminAreas {
+			if strings.EqualFold(adminArea.ID, administrativeArea) {
+				return adminArea.ID
+			}
+		}
+	}
+
+	for _, adminAreas := range data.AdministrativeAreas {
+		for _, adminArea := range adminAreas {
+			if strings.EqualFold(adminArea.Name, administrativeArea) || strings.EqualFold(adminArea.PostalKey, administrativeArea) {
+				return adminArea.ID
+			}
+		}
+	}
+
+	return ""
+}
+
+// getLocalityID returns the ID of a locality given its ID or name in any of the languages available for the country.
+// If the locality cannot be found, an empty string is returned.
+func (d data) getLocalityID(countryCode, administrativeAreaID, locality string) string {
+
+	data := d.getCountry(countryCode)
+
+	for _, adminAreas := range data.AdministrativeAreas {
+		for _, adminArea := range adminAreas {
+			if adminArea.ID == administrativeAreaID {
+				for _, l := range adminArea.Localities {
+					if strings.EqualFold(l.ID, locality) || strings.EqualFold(l.Name, locality) {
+						return l.ID
+					}
+				}
+			}
+		}
+	}
+
+	return ""
+}
+
+// getDependentLocalityID returns the ID of a dependent locality given its ID or name in any of the languages available
+// for the country. If the dependent locality cannot be found, an empty string is returned.
+func (d data) getDependentLocalityID(countryCode, administrativeAreaID, localityID, dependentLocality string) string {
+
+	data := d.getCountry(countryCode)
+
+	for _, adminAreas := range data.AdministrativeAreas {
+		for _, adminArea := range adminAreas {
+			if adminArea.ID == administrativeAreaID {
+				for _, l := range adminArea.Localities {
+					if l.ID == localityID {
+						for _, dl := range l.DependentLocalities {
+							if strings.EqualFold(dl.ID, dependentLocality) || strings.EqualFold(dl.Name, dependentLocality) {
+								return dl.ID
+							}
+						}
+					}
+				}
+			}
+		}
+	}
+
+	return ""
+}
+
+// getAdministrativeAreaRedirect returns the redirect of an administrative area that is also a country given its ID,
+// name, latinized name or postal key.
+func (d data) getAdministrativeAreaRedirect(countryCode, administrativeArea string) (administrativeAreaRedirect, bool) {
+
+	administrativeArea = strings.TrimSpace(administrativeArea)
+
+	if administrativeArea == "" {
+		return administrativeAreaRedirect{}, false
+	}
+
+	for _, redirect := range d.getCountry(countryCode).AdministrativeAreaRedirects {
+		for _, value := range []string{redirect.ID, redirect.Name, redirect.LatinizedName, redirect.PostalKey} {
+			if strings.EqualFold(value, administrativeArea) {
+				return redirect, true
+			}
+		}
+	}
+
+	return administrativeAreaRedirect{}, false
+}
+
+// resolveSubdivisions converts the administrative area, locality and dependent locality of an address from their
+// names into their keys where possible.
+func (d data) resolveSubdivisions(address Address) Address {
+
+	if id := d.getAdministrativeAreaID(address.Country, address.AdministrativeArea); id != "" {
+		address.AdministrativeArea = id
+	}
+
+	if id := d.getLocalityID(address.Country, address.AdministrativeArea, address.Locality); id != "" {
+		address.Locality = id
+	}
+
+	if id := d.getDependentLocalityID(address.Country, address.AdministrativeArea, address.Locality, address.DependentLocality); id != "" {
+		address.DependentLocality = id
+	}
+
+	return address
+}
+
+// getCountryCode returns the ISO 3166-1 code of a country given its code or its name in English, the country's
+// default language or any of the additional languages. If the country cannot be found, an empty string is returned.
+func (d data) getCountryCode(country string, languages ...string) string {
+
+	country = strings.TrimSpace(country)
+
+	if code := strings.ToUpper(country); code != "ZZ" && d.hasCountry(code) {
+		return code
+	}
+
+	for _, countryCode := range d.countryCodes() {
+
+		if countryCode == "ZZ" {
+			continue
+		}
+
+		if strings.EqualFold(d.name(countryCode), country) {
+			return countryCode
+		}
+
+		region := textLanguage.MustParseRegion(countryCode)
+
+		for _, language := range append([]string{"en", d.defaultLanguage(countryCode)}, languages...) {
+
+			tag, err := textLanguage.Parse(language)
+
+			if err != nil {
+				continue
+			}
+
+			if namer := display.Regions(tag); namer != nil && strings.EqualFold(namer.Name(region), country) {
+				return countryCode
+			}
+		}
+	}
+
+	return ""
+}
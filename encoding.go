@@ -0,0 +1,114 @@
+package address
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/Boostport/address/internal/addressdata"
+)
+
+// The generated data is encoded in the compact binary format of the addressdata package, so that it does not need to
+// be initialized when the program starts and each country is only decoded the first time it is used.
+
+// encodedData holds the encoded countries and decodes them when they are first used. It is safe for concurrent use.
+type encodedData struct {
+	index     map[string]addressdata.IndexEntry
+	countries []byte
+
+	mu      sync.RWMutex
+	decoded map[string]country
+}
+
+// newEncodedData decodes the index of the encoded data. The countries are decoded when they are first used.
+func newEncodedData(encoded []byte) (*encodedData, error) {
+
+	index, countries, err := addressdata.DecodeIndex(encoded)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &encodedData{
+		index:     index,
+		countries: countries,
+		decoded:   map[string]country{},
+	}, nil
+}
+
+// mustDecodeData decodes the index of the data generated by the generator, which is checked by the tests.
+func mustDecodeData(encoded []byte) *encodedData {
+
+	d, err := newEncodedData(encoded)
+
+	if err != nil {
+		panic(err)
+	}
+
+	return d
+}
+
+func (e *encodedData) hasCountry(countryCode string) bool {
+	_, ok := e.index[countryCode]
+	return ok
+}
+
+func (e *encodedData) countryCodes() []string {
+
+	codes := make([]string, 0, len(e.index))
+
+	for code := range e.index {
+		codes = append(codes, code)
+	}
+
+	sort.Strings(codes)
+
+	return codes
+}
+
+// country returns a country, decoding it if it has not been used before. The encoded data is generated and checked by
+// the tests, so a country that cannot be decoded is a bug and causes a panic.
+func (e *encodedData) country(countryCode string) (country, bool) {
+
+	entry, ok := e.index[countryCode]
+
+	if !ok {
+		return country{}, false
+	}
+
+	e.mu.RLock()
+	c, ok := e.decoded[countryCode]
+	e.mu.RUnlock()
+
+	if ok {
+		return c, true
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if c, ok = e.decoded[countryCode]; ok {
+		return c, true
+	}
+
+	c, err := decodeCountry(e.countries[entry.Offset : entry.Offset+entry.Length])
+
+	if err != nil {
+		panic(fmt.Sprintf("address: unable to decode %s: %s", countryCode, err))
+	}
+
+	e.decoded[countryCode] = c
+
+	return c, true
+}
+
+func decodeCountry(encoded []byte) (country, error) {
+
+	c, err := addressdata.DecodeCountry(encoded)
+
+	if err != nil {
+		return country{}, err
+	}
+
+	return newCountry(c), nil
+}
@@ -0,0 +1,204 @@
+package address
+
+import (
+	"errors"
+	"runtime"
+	"testing"
+
+	"github.com/Boostport/address/internal/addressdata"
+)
+
+func TestEncodedData(t *testing.T) {
+
+	encoded, err := newEncodedData(generatedData)
+
+	if err != nil {
+		t.Fatalf("Unexpected error decoding the index of the generated data: %s", err)
+	}
+
+	for _, countryCode := range encoded.countryCodes() {
+
+		c, err := decodeCountry(encoded.countries[encoded.index[countryCode].Offset : encoded.index[countryCode].Offset+encoded.index[countryCode].Length])
+
+		if err != nil {
+			t.Errorf("Unexpected error decoding %s: %s", countryCode, err)
+			continue
+		}
+
+		if c.ID != countryCode || c.Name != encoded.index[countryCode].Name || c.DefaultLanguage != encoded.index[countryCode].DefaultLanguage {
+			t.Errorf("Decoded country %s (%s, %s) does not match its index entry", c.ID, c.Name, c.DefaultLanguage)
+		}
+	}
+}
+
+func TestEncodedDataCorrupt(t *testing.T) {
+
+	testCases := [][]byte{
+		nil,
+		[]byte("ADDX\x01\x00"),
+		[]byte("ADDR\x05\x00"),
+		[]byte("ADDR\x04\x05"),
+		[]byte("ADDR\x04\x01\x02AU\x00\x00\x00\x10"),
+		generatedData[:len(generatedData)/2],
+	}
+
+	for i, testCase := range testCases {
+		if _, err := newEncodedData(testCase); !errors.Is(err, addressdata.ErrCorruptData) {
+			t.Errorf("Expected ErrCorruptData for test case %d, got %v", i, err)
+		}
+	}
+
+	encoded := mustDecodeData(generatedData)
+	entry := encoded.index["AU"]
+	au := encoded.countries[entry.Offset : entry.Offset+entry.Length]
+
+	for i, countryData := range [][]byte{au[:len(au)-1], append(append([]byte{}, au...), 0)} {
+		if _, err := decodeCountry(countryData); !errors.Is(err, addressdata.ErrCorruptData) {
+			t.Errorf("Expected ErrCorruptData for country test case %d, got %v", i, err)
+		}
+	}
+}
+
+// decodeAll decodes all the countries into a map, which is how the data was stored before it was encoded.
+func decodeAll(b *testing.B) map[string]country {
+
+	encoded, err := newEncodedData(generatedData)
+
+	if err != nil {
+		b.Fatalf("Unexpected error decoding data: %s", err)
+	}
+
+	countries := map[string]country{}
+
+	for _, countryCode := range encoded.countryCodes() {
+		countries[countryCode], _ = encoded.country(countryCode)
+	}
+
+	return countries
+}
+
+// BenchmarkStartup measures the work done when the program starts, which is decoding the index of the data.
+func BenchmarkStartup(b *testing.B) {
+
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		if _, err := newEncodedData(generatedData); err != nil {
+			b.Fatalf("Unexpected error decoding data: %s", err)
+		}
+	}
+}
+
+// BenchmarkStartupDecodeAll measures decoding all the countries up front, as the map of countries was initialized
+// when the program started.
+func BenchmarkStartupDecodeAll(b *testing.B) {
+
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		decodeAll(b)
+	}
+}
+
+// BenchmarkHeapInUse reports the heap in use after decoding the index, compared with decoding all the countries.
+func BenchmarkHeapInUse(b *testing.B) {
+
+	var lazy *encodedData
+	var countries map[string]country
+
+	for i := 0; i < b.N; i++ {
+
+		before := heapInUse()
+
+		lazy = mustDecodeData(generatedData)
+
+		afterLazy := heapInUse()
+
+		countries = decodeAll(b)
+
+		afterAll := heapInUse()
+
+		b.ReportMetric(float64(afterLazy-before), "lazy-heap-bytes")
+		b.ReportMetric(float64(afterAll-afterLazy), "map-heap-bytes")
+	}
+
+	runtime.KeepAlive(lazy)
+	runtime.KeepAlive(countries)
+}
+
+func heapInUse() int64 {
+
+	var stats runtime.MemStats
+
+	runtime.GC()
+	runtime.ReadMemStats(&stats)
+
+	return int64(stats.HeapInuse)
+}
+
+// BenchmarkGetCountryFirst measures the first lookup of a country, which decodes it.
+func BenchmarkGetCountryFirst(b *testing.B) {
+
+	for _, countryCode := range []string{"AU", "CN"} {
+		b.Run(countryCode, func(b *testing.B) {
+
+			b.ReportAllocs()
+
+			for i := 0; i < b.N; i++ {
+
+				b.StopTimer()
+				encoded := mustDecodeData(generatedData)
+				b.StartTimer()
+
+				encoded.country(countryCode)
+			}
+		})
+	}
+}
+
+// BenchmarkGetCountrySubsequent measures lookups of a country that has already been decoded.
+func BenchmarkGetCountrySubsequent(b *testing.B) {
+
+	for _, countryCode := range []string{"AU", "CN"} {
+		b.Run(countryCode, func(b *testing.B) {
+
+			registry := &Registry{
+				data: data{
+					encoded: mustDecodeData(generatedData),
+				},
+			}
+
+			registry.GetCountry(countryCode)
+
+			b.ReportAllocs()
+			b.ResetTimer()
+
+			for i := 0; i < b.N; i++ {
+				registry.GetCountry(countryCode)
+			}
+		})
+	}
+}
+
+// BenchmarkGetCountryMap measures lookups of a country in a map of decoded countries, which is how the data was
+// stored before it was encoded.
+func BenchmarkGetCountryMap(b *testing.B) {
+
+	for _, countryCode := range []string{"AU", "CN"} {
+		b.Run(countryCode, func(b *testing.B) {
+
+			registry := &Registry{
+				data: data{
+					countries: decodeAll(b),
+				},
+			}
+
+			b.ReportAllocs()
+			b.ResetTimer()
+
+			for i := 0; i < b.N; i++ {
+				registry.GetCountry(countryCode)
+			}
+		})
+	}
+}
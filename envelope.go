@@ -0,0 +1,269 @@
+package address
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"golang.org/x/text/encoding/charmap"
+)
+
+// CountryLinePlacement determines where the destination country is placed when rendering an envelope or label.
+type CountryLinePlacement int
+
+const (
+	// CountryLineLast places the country on the last line of the address, as recommended by the UPU, even for countries
+	// where the address is formatted from major-to-minor (such as China).
+	CountryLineLast CountryLinePlacement = iota
+	// CountryLineAsFormatted leaves the country where the formatter places it.
+	CountryLineAsFormatted
+)
+
+// EnvelopeOptions configures the rendering of an envelope or label as SVG or PDF. All sizes are in points (1/72 of an
+// inch). A zero value uses the default, which is a DL envelope (220 x 110 mm) with 36 point margins and a 12 point font.
+// If ReturnAddress is set, it is printed in the top left corner using ReturnFontSize (defaulting to 3/4 of the
+// FontSize) and the address is printed in the lower right of the envelope, as recommended by the UPU. Otherwise, the
+// address is printed in the top left corner, which suits labels.
+type EnvelopeOptions struct {
+	Width                float64
+	Height               float64
+	Margin               float64
+	FontSize             float64
+	ReturnAddress        *Address
+	ReturnFontSize       float64
+	CountryLinePlacement CountryLinePlacement
+}
+
+func (o EnvelopeOptions) withDefaults() EnvelopeOptions {
+
+	if o.Width <= 0 {
+		o.Width = 623.6
+	}
+
+	if o.Height <= 0 {
+		o.Height = 311.8
+	}
+
+	if o.Margin <= 0 {
+		o.Margin = 36
+	}
+
+	if o.FontSize <= 0 {
+		o.FontSize = 12
+	}
+
+	if o.ReturnFontSize <= 0 {
+		o.ReturnFontSize = o.FontSize * 0.75
+	}
+
+	return o
+}
+
+const (
+	// envelopeLineHeight is the height of a line relative to the font size.
+	envelopeLineHeight = 1.2
+	// envelopeCharWidth is the average width of a character relative to the font size. It is used to estimate how many
+	// characters fit on a line.
+	envelopeCharWidth = 0.6
+)
+
+type envelopeLine struct {
+	x        float64
+	y        float64
+	fontSize float64
+	text     string
+}
+
+// FormatSVG formats an address for a postal label and renders it onto an envelope or label as an SVG image.
+// The address is laid out as in FormatWithLimits, using the number of lines and characters that fit within the
+// margins. The return address is formatted in the same way, with the country included if it is different from the
+// country of the address.
+func (f PostalLabelFormatter) FormatSVG(address Address, language string, options EnvelopeOptions) (string, error) {
+
+	options = options.withDefaults()
+
+	lines, err := f.envelopeLayout(address, language, options, func(r rune) bool { return true })
+
+	if err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%spt" height="%spt" viewBox="0 0 %s %s">`+"\n",
+		formatPoints(options.Width), formatPoints(options.Height), formatPoints(options.Width), formatPoints(options.Height))
+
+	for _, line := range lines {
+
+		fmt.Fprintf(&b, `<text x="%s" y="%s" font-family="Helvetica, Arial, sans-serif" font-size="%s">`,
+			formatPoints(line.x), formatPoints(line.y), formatPoints(line.fontSize))
+
+		if err := xml.EscapeText(&b, []byte(line.text)); err != nil {
+			return "", err
+		}
+
+		b.WriteString("</text>\n")
+	}
+
+	b.WriteString("</svg>\n")
+
+	return b.String(), nil
+}
+
+// FormatPDF formats an address for a postal label and renders it onto an envelope or label as a single page PDF
+// document. The layout is the same as FormatSVG. The text is set in Helvetica using the Windows-1252 encoding, so
+// if the address contains characters outside of it, the latinized format, the latinized names of the subdivisions and
+// the English name of the country are used instead. If that does not help, an ErrUnsupportedCharacters is returned.
+func (f PostalLabelFormatter) FormatPDF(address Address, language string, options EnvelopeOptions) ([]byte, error) {
+
+	options = options.withDefaults()
+
+	lines, err := f.envelopeLayout(address, language, options, isWindows1252Rune)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var content bytes.Buffer
+
+	encoder := charmap.Windows1252.NewEncoder()
+
+	for _, line := range lines {
+
+		text, err := encoder.String(line.text)
+
+		if err != nil {
+			return nil, err
+		}
+
+		fmt.Fprintf(&content, "BT /F1 %s Tf %s %s Td (%s) Tj ET\n",
+			formatPoints(line.fontSize), formatPoints(line.x), formatPoints(options.Height-line.y), pdfEscaper.Replace(text))
+	}
+
+	objects := []string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %s %s] /Resources << /Font << /F1 4 0 R >> >> /Contents 5 0 R >>",
+			formatPoints(options.Width), formatPoints(options.Height)),
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>",
+		fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", content.Len(), content.String()),
+	}
+
+	var pdf bytes.Buffer
+
+	pdf.WriteString("%PDF-1.4\n")
+
+	offsets := make([]int, len(objects))
+
+	for i, object := range objects {
+		offsets[i] = pdf.Len()
+		fmt.Fprintf(&pdf, "%d 0 obj\n%s\nendobj\n", i+1, object)
+	}
+
+	xref := pdf.Len()
+
+	fmt.Fprintf(&pdf, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+
+	for _, offset := range offsets {
+		fmt.Fprintf(&pdf, "%010d 00000 n \n", offset)
+	}
+
+	fmt.Fprintf(&pdf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, xref)
+
+	return pdf.Bytes(), nil
+}
+
+var pdfEscaper = strings.NewReplacer(`\`, `\\`, `(`, `\(`, `)`, `\)`, "\r", `\r`)
+
+// envelopeLayout lays out the address and return address on the envelope. Each line is positioned using the baseline
+// of its text, measured from the top left corner of the envelope.
+func (f PostalLabelFormatter) envelopeLayout(address Address, language string, options EnvelopeOptions, supportsRune func(r rune) bool) ([]envelopeLine, error) {
+
+	var result []envelopeLine
+
+	x := options.Margin
+	y := options.Margin
+
+	if options.ReturnAddress != nil {
+
+		returnFormatter := f
+		returnFormatter.OriginCountryCode = address.Country
+
+		lines, err := returnFormatter.printableLayout(*options.ReturnAddress, language, envelopeLimits(options.Width/2-options.Margin, options.Height/2-options.Margin, options.ReturnFontSize), supportsRune)
+
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, positionEnvelopeLines(lines, x, y, options.ReturnFontSize, options.CountryLinePlacement)...)
+
+		x = options.Width / 2
+		y = options.Height / 2
+	}
+
+	lines, err := f.printableLayout(address, language, envelopeLimits(options.Width-options.Margin-x, options.Height-options.Margin-y, options.FontSize), supportsRune)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if options.ReturnAddress != nil {
+		// Align the address to the bottom margin
+		y = options.Height - options.Margin - float64(len(lines))*options.FontSize*envelopeLineHeight
+	}
+
+	return append(result, positionEnvelopeLines(lines, x, y, options.FontSize, options.CountryLinePlacement)...), nil
+}
+
+// envelopeLimits estimates the number of lines and characters per line that fit within an area.
+func envelopeLimits(width, height, fontSize float64) LabelLimits {
+	return LabelLimits{
+		MaxLines: max(1, int(height/(fontSize*envelopeLineHeight))),
+		MaxWidth: max(1, int(width/(fontSize*envelopeCharWidth))),
+	}
+}
+
+func positionEnvelopeLines(lines []layoutLine, x, top, fontSize float64, placement CountryLinePlacement) []envelopeLine {
+
+	if placement == CountryLineLast {
+		lines = moveCountryLineLast(lines)
+	}
+
+	result := make([]envelopeLine, 0, len(lines))
+
+	for i, line := range lines {
+		result = append(result, envelopeLine{
+			x:        x,
+			y:        top + fontSize + float64(i)*fontSize*envelopeLineHeight,
+			fontSize: fontSize,
+			text:     line.String(),
+		})
+	}
+
+	return result
+}
+
+// moveCountryLineLast moves the line containing only the country to the end of the address.
+func moveCountryLineLast(lines []layoutLine) []layoutLine {
+
+	for i, line := range lines {
+
+		if fields := line.fields(); len(fields) != 1 || fields[0] != Country {
+			continue
+		}
+
+		result := append(append([]layoutLine{}, lines[:i]...), lines[i+1:]...)
+
+		return append(result, line)
+	}
+
+	return lines
+}
+
+// formatPoints formats a size in points to at most 2 decimal places, so that the output is stable.
+func formatPoints(points float64) string {
+	return strconv.FormatFloat(math.Round(points*100)/100, 'f', -1, 64)
+}
@@ -0,0 +1,202 @@
+package address
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestFormatSVG(t *testing.T) {
+
+	recipient := New(
+		WithName("John Smith"),
+		WithStreetAddress([]string{
+			"1 Main Street",
+		}),
+		WithLocality("东城区"),
+		WithAdministrativeArea("11"),
+		WithPostCode("100000"),
+		WithCountry("CN"),
+	)
+
+	sender := New(
+		WithName("Jane & Co"),
+		WithStreetAddress([]string{
+			"525 Collins Street",
+		}),
+		WithLocality("Melbourne"),
+		WithAdministrativeArea("VIC"),
+		WithPostCode("3000"),
+		WithCountry("AU"),
+	)
+
+	testCases := []struct {
+		Options  EnvelopeOptions
+		Expected string
+	}{
+		{
+			Options: EnvelopeOptions{
+				Width:    288,
+				Height:   144,
+				Margin:   18,
+				FontSize: 10,
+			},
+			Expected: `<svg xmlns="http://www.w3.org/2000/svg" width="288pt" height="144pt" viewBox="0 0 288 144">
+<text x="18" y="28" font-family="Helvetica, Arial, sans-serif" font-size="10">100000</text>
+<text x="18" y="40" font-family="Helvetica, Arial, sans-serif" font-size="10">北京市东城区</text>
+<text x="18" y="52" font-family="Helvetica, Arial, sans-serif" font-size="10">1 Main Street</text>
+<text x="18" y="64" font-family="Helvetica, Arial, sans-serif" font-size="10">John Smith</text>
+<text x="18" y="76" font-family="Helvetica, Arial, sans-serif" font-size="10">CHINA</text>
+</svg>
+`,
+		},
+		{
+			Options: EnvelopeOptions{
+				ReturnAddress:        &sender,
+				CountryLinePlacement: CountryLineAsFormatted,
+			},
+			Expected: `<svg xmlns="http://www.w3.org/2000/svg" width="623.6pt" height="311.8pt" viewBox="0 0 623.6 311.8">
+<text x="36" y="45" font-family="Helvetica, Arial, sans-serif" font-size="9">Jane &amp; Co</text>
+<text x="36" y="55.8" font-family="Helvetica, Arial, sans-serif" font-size="9">525 Collins Street</text>
+<text x="36" y="66.6" font-family="Helvetica, Arial, sans-serif" font-size="9">MELBOURNE VIC 3000</text>
+<text x="36" y="77.4" font-family="Helvetica, Arial, sans-serif" font-size="9">澳大利亚 - AUSTRALIA</text>
+<text x="311.8" y="215.8" font-family="Helvetica, Arial, sans-serif" font-size="12">CHINA</text>
+<text x="311.8" y="230.2" font-family="Helvetica, Arial, sans-serif" font-size="12">100000</text>
+<text x="311.8" y="244.6" font-family="Helvetica, Arial, sans-serif" font-size="12">北京市东城区</text>
+<text x="311.8" y="259" font-family="Helvetica, Arial, sans-serif" font-size="12">1 Main Street</text>
+<text x="311.8" y="273.4" font-family="Helvetica, Arial, sans-serif" font-size="12">John Smith</text>
+</svg>
+`,
+		},
+	}
+
+	f := PostalLabelFormatter{
+		OriginCountryCode: "AU",
+	}
+
+	for i, testCase := range testCases {
+
+		svg, err := f.FormatSVG(recipient, "zh", testCase.Options)
+
+		if err != nil {
+			t.Errorf("Unexpected error rendering test case %d: %s", i, err)
+			continue
+		}
+
+		if svg != testCase.Expected {
+			t.Errorf("SVG for test case %d does not match the expected result, got %s", i, svg)
+		}
+	}
+}
+
+func TestFormatSVGReturnAddressUsesFormatterSettings(t *testing.T) {
+
+	warehouse := CountryData{
+		Format:          "%N%n%O%n%A%n%C",
+		Required:        []Field{Locality, StreetAddress},
+		Allowed:         []Field{Locality, Name, Organization, StreetAddress},
+		DefaultLanguage: "en",
+		AdministrativeAreas: map[string][]AdministrativeAreaData{
+			"en": {},
+		},
+	}
+
+	recipient := New(
+		WithName("John Smith"),
+		WithStreetAddress([]string{
+			"525 Collins Street",
+		}),
+		WithLocality("Melbourne"),
+		WithAdministrativeArea("VIC"),
+		WithPostCode("3000"),
+		WithCountry("AU"),
+	)
+
+	sender := New(
+		WithName("Jörg Müller"),
+		WithStreetAddress([]string{
+			"Dock 4",
+		}),
+		WithLocality("Building B"),
+		WithCountry("XW"),
+	)
+
+	f := PostalLabelFormatter{
+		OriginCountryCode: "XW",
+		Latinize:          true,
+		Transliterator: TransliteratorFunc(func(text string) string {
+			return strings.NewReplacer("ö", "oe", "ü", "ue").Replace(text)
+		}),
+		Registry: NewRegistry(WithCountryData("XW", "Central Warehouse", warehouse)),
+	}
+
+	svg, err := f.FormatSVG(recipient, "en", EnvelopeOptions{ReturnAddress: &sender})
+
+	if err != nil {
+		t.Fatalf("Unexpected error rendering SVG: %s", err)
+	}
+
+	for _, expected := range []string{">Joerg Mueller</text>", ">Dock 4</text>", ">BUILDING B</text>", ">CENTRAL WAREHOUSE</text>"} {
+		if !strings.Contains(svg, expected) {
+			t.Errorf("Expected the return address in the SVG to contain %s, got %s", expected, svg)
+		}
+	}
+}
+
+func TestFormatPDF(t *testing.T) {
+
+	recipient := New(
+		WithName("John Smith"),
+		WithStreetAddress([]string{
+			"1 Main Street",
+		}),
+		WithLocality("东城区"),
+		WithAdministrativeArea("11"),
+		WithPostCode("100000"),
+		WithCountry("CN"),
+	)
+
+	sender := New(
+		WithName("Jane (Citizen)"),
+		WithStreetAddress([]string{
+			"525 Collins Street",
+		}),
+		WithLocality("Melbourne"),
+		WithAdministrativeArea("VIC"),
+		WithPostCode("3000"),
+		WithCountry("AU"),
+	)
+
+	pdf, err := PostalLabelFormatter{OriginCountryCode: "AU"}.FormatPDF(recipient, "zh", EnvelopeOptions{ReturnAddress: &sender})
+
+	if err != nil {
+		t.Fatalf("Unexpected error rendering PDF: %s", err)
+	}
+
+	if !bytes.HasPrefix(pdf, []byte("%PDF-1.4\n")) || !bytes.HasSuffix(pdf, []byte("%%EOF\n")) {
+		t.Errorf("PDF does not have a valid header and trailer")
+	}
+
+	expectedContent := "BT /F1 9 Tf 36 266.8 Td (Jane \\(Citizen\\)) Tj ET\n" +
+		"BT /F1 9 Tf 36 256 Td (525 Collins Street) Tj ET\n" +
+		"BT /F1 9 Tf 36 245.2 Td (MELBOURNE VIC 3000) Tj ET\n" +
+		"BT /F1 9 Tf 36 234.4 Td (AUSTRALIA) Tj ET\n" +
+		"BT /F1 12 Tf 311.8 96 Td (John Smith) Tj ET\n" +
+		"BT /F1 12 Tf 311.8 81.6 Td (1 Main Street) Tj ET\n" +
+		"BT /F1 12 Tf 311.8 67.2 Td (Dongcheng Qu) Tj ET\n" +
+		"BT /F1 12 Tf 311.8 52.8 Td (BEIJING SHI, 100000) Tj ET\n" +
+		"BT /F1 12 Tf 311.8 38.4 Td (CHINA) Tj ET\n"
+
+	if !bytes.Contains(pdf, []byte("stream\n"+expectedContent+"endstream")) {
+		t.Errorf("PDF content stream does not match the expected result, got %s", pdf)
+	}
+
+	lines := strings.Split(strings.TrimSpace(string(pdf)), "\n")
+
+	xref, err := strconv.Atoi(lines[len(lines)-2])
+
+	if err != nil || !bytes.HasPrefix(pdf[xref:], []byte("xref\n")) {
+		t.Errorf("PDF startxref does not point to the cross-reference table")
+	}
+}
@@ -9,6 +9,11 @@ import (
 // ErrInvalidCountryCode indicate that the country code used to create an address is invalid.
 var ErrInvalidCountryCode = errors.New("invalid country code")
 
+// ErrCountryNotCompiledIn indicates that the country code is valid, but the country's data was left out when the data
+// was generated using the -countries flag of the generator. It wraps ErrInvalidCountryCode, so checking for
+// ErrInvalidCountryCode using errors.Is also matches countries that are not compiled in.
+var ErrCountryNotCompiledIn = fmt.Errorf("%w: country not compiled in", ErrInvalidCountryCode)
+
 // ErrInvalidDependentLocality indicates that the dependent locality is invalid. This is usually due to the country having
 // a pre-determined list of dependent localities and the value does not match any of the keys in the list of dependent localities.
 var ErrInvalidDependentLocality = errors.New("invalid dependent locality")
@@ -24,6 +29,22 @@ var ErrInvalidAdministrativeArea = errors.New("invalid administrative area")
 // ErrInvalidPostCode indicates that the post code did not valid using the regular expressions of the country.
 var ErrInvalidPostCode = errors.New("invalid post code")
 
+// ErrInvalidTemplate indicates that the template produced by an Outputter could not be parsed or executed.
+var ErrInvalidTemplate = errors.New("invalid template")
+
+// ErrInvalidVCardADR indicates that a vCard ADR property or jCard adr property could not be parsed.
+var ErrInvalidVCardADR = errors.New("invalid vCard ADR")
+
+// ErrInvalidPostalAddress indicates that a schema.org PostalAddress could not be parsed from JSON-LD.
+var ErrInvalidPostalAddress = errors.New("invalid schema.org PostalAddress")
+
+// ErrInvalidXML indicates that an xAL or UPU S42 document could not be decoded.
+var ErrInvalidXML = errors.New("invalid address XML")
+
+// ErrInvalidAddressData indicates that address data in the format of Google's Address Data Service could not be loaded,
+// either because a file is missing or could not be decoded, or because the data failed a sanity check.
+var ErrInvalidAddressData = errors.New("invalid address data")
+
 // ErrMissingRequiredFields indicates the a required address field is missing. The Fields field can be used to get a list
 // of missing fields.
 type ErrMissingRequiredFields struct {
@@ -59,3 +80,58 @@ func (e ErrUnsupportedFields) Error() string {
 
 	return fmt.Sprintf("unsupported fields for %s: %s", e.country, strings.Join(fieldsStr, ","))
 }
+
+// ErrAdministrativeAreaIsCountry indicates that the administrative area of an address is also a separate country, such
+// as Hong Kong in China. The CountryCode field contains the country code the address should use instead, and
+// NormalizeCountry can be used to rewrite the address. It wraps ErrInvalidAdministrativeArea, so checking for
+// ErrInvalidAdministrativeArea using errors.Is also matches it.
+type ErrAdministrativeAreaIsCountry struct {
+	country            string
+	AdministrativeArea string
+	CountryCode        string
+}
+
+func (e ErrAdministrativeAreaIsCountry) Error() string {
+	return fmt.Sprintf("invalid administrative area for %s: %s is a separate country, use the country code %s", e.country, e.AdministrativeArea, e.CountryCode)
+}
+
+func (e ErrAdministrativeAreaIsCountry) Unwrap() error {
+	return ErrInvalidAdministrativeArea
+}
+
+// ErrLabelOverflow indicates that an address does not fit within the limits of a postal label. The Fields field can be
+// used to get a list of fields that could not fit.
+type ErrLabelOverflow struct {
+	country string
+	Fields  []Field
+}
+
+func (e ErrLabelOverflow) Error() string {
+
+	var fieldsStr []string
+
+	for _, field := range e.Fields {
+		fieldsStr = append(fieldsStr, field.String())
+	}
+
+	return fmt.Sprintf("fields do not fit on the label for %s: %s", e.country, strings.Join(fieldsStr, ","))
+}
+
+// ErrUnsupportedCharacters indicates that an address contains characters that the font of a label printer cannot
+// render, even after falling back to the latinized format. The Fields field can be used to get a list of fields
+// containing those characters.
+type ErrUnsupportedCharacters struct {
+	country string
+	Fields  []Field
+}
+
+func (e ErrUnsupportedCharacters) Error() string {
+
+	var fieldsStr []string
+
+	for _, field := range e.Fields {
+		fieldsStr = append(fieldsStr, field.String())
+	}
+
+	return fmt.Sprintf("fields contain characters the font cannot render for %s: %s", e.country, strings.Join(fieldsStr, ","))
+}
@@ -4,11 +4,12 @@ import (
 	"bytes"
 	"fmt"
 	"html/template"
+	"io"
 	"regexp"
 	"strings"
+	textTemplate "text/template"
 
 	textLanguage "golang.org/x/text/language"
-	"golang.org/x/text/language/display"
 )
 
 var isAlphabetRegex = regexp.MustCompile(`[A-Za-z]`)
@@ -25,24 +26,71 @@ var funcMap = template.FuncMap{
 	"inc": func(i int) int {
 		return i + 1
 	},
-	"join": strings.Join,
+	"join":     strings.Join,
+	"label":    fieldNameLabel,
+	"markdown": escapeMarkdown,
+}
+
+// Formatter formats an address into a string. It is implemented by DefaultFormatter, PostalLabelFormatter,
+// SingleLineFormatter and MilitaryFormatter.
+//
+// The formatters share some of their fields. If Latinize is set to true and a Transliterator is set, the free-text
+// fields (such as the name and street address) are also transliterated into the latin alphabet. If Registry is set,
+// its data and overrides are used instead of the default registry. Formatters with an Output panic in Format if the
+// Outputter produces a template that cannot be parsed, and return the output produced up to the error if the template
+// fails to execute. Use FormatE, which returns an error instead, when using a custom Outputter.
+type Formatter interface {
+	Format(address Address, language string) string
 }
 
 // DefaultFormatter formats an address using the country's address format and includes the name of the country.
 // If Latinize is set to true, in countries where a latinized address format is provided, the latinized format is used.
+// See Formatter for the Transliterator and Registry fields.
 type DefaultFormatter struct {
-	Output   Outputter
-	Latinize bool
+	Output         Outputter
+	Latinize       bool
+	Transliterator Transliterator
+	Registry       *Registry
 }
 
 // Format formats an address. The language must be a valid ISO 639-1 language code. It is used to convert the keys
 // in administrative areas, localities and dependent localities into their actual names. If the provided language
-// does not have any translations, it falls back to the default language used by the country.
+// does not have any translations, it falls back to the default language used by the country. See Formatter for how
+// template errors are handled.
 func (d DefaultFormatter) Format(address Address, language string) string {
 
-	language = generated.normalizeLanguage(address.Country, language)
+	format, addressData := d.prepare(address, language)
+
+	return mustFormatTemplate(d.Output, format, map[Field]struct{}{}, addressData)
+}
+
+// FormatE formats an address in the same way as Format, but returns an error instead of panicking if the Outputter
+// produces an invalid template or the template fails to execute. The returned error wraps ErrInvalidTemplate.
+func (d DefaultFormatter) FormatE(address Address, language string) (string, error) {
+
+	format, addressData := d.prepare(address, language)
+
+	return formatTemplate(d.Output, format, map[Field]struct{}{}, addressData)
+}
+
+// FormatLines formats an address into lines of tokens instead of a string, so that the layout can be reused in
+// places such as PDFs or native mobile user interfaces without losing which field each piece of text came from.
+// The Output field is not used.
+func (d DefaultFormatter) FormatLines(address Address, language string) [][]FormattedToken {
+
+	format, addressData := d.prepare(address, language)
+
+	return layoutLinesToTokens(layoutFormat(format, addressData, map[Field]struct{}{}))
+}
+
+// prepare returns the address format and the data to merge into it.
+func (d DefaultFormatter) prepare(address Address, language string) (string, formatData) {
 
-	format, isLatinized := getFormat(address.Country, d.Latinize)
+	registry := registryOrDefault(d.Registry)
+
+	language = registry.normalizeLanguage(address.Country, language)
+
+	format, isLatinized := registry.getFormat(address.Country, d.Latinize)
 
 	if isLatinized {
 		format += "%n%country"
@@ -50,15 +98,28 @@ func (d DefaultFormatter) Format(address Address, language string) string {
 		format = "%country%n" + format
 	}
 
-	t := d.Output.TransformFormat(format, map[Field]struct{}{})
+	return format, address.toFormatData(registry, registry.getCountry(address.Country), language).transliterate(d.Latinize, d.Transliterator)
+}
 
-	compiled := template.Must(template.New("").Funcs(funcMap).Parse(t))
+// BilingualAddress contains an address formatted in the local script of the country as well as in the latin alphabet.
+type BilingualAddress struct {
+	Local     string
+	Latinized string
+}
 
-	buf := bytes.NewBuffer([]byte{})
+// FormatBilingual formats an address using the country's local format and names of the subdivisions in the language,
+// as well as using the latinized format and the latinized names of the subdivisions. This is useful for paperwork
+// such as customs declarations that need both. The Latinize field is ignored. In countries without a latinized format
+// or latinized names, both versions can be the same.
+func (d DefaultFormatter) FormatBilingual(address Address, language string) (BilingualAddress, error) {
+
+	local := d
+	local.Latinize = false
 
-	compiled.Execute(buf, address.toFormatData(generated.getCountry(address.Country), language))
+	latinized := d
+	latinized.Latinize = true
 
-	return collapseBRRegex.ReplaceAllString(collapseWhitespaceRegex.ReplaceAllString(strings.TrimSpace(buf.String()), "\n"), "<br>")
+	return formatBilingual(registryOrDefault(d.Registry), local, latinized, address, language)
 }
 
 // PostalLabelFormatter formats an address for postal labels. It uppercases address fields as required by the country's
@@ -67,44 +128,102 @@ func (d DefaultFormatter) Format(address Address, language string) string {
 // recommendations of the Universal Postal Union, to avoid difficulties in transit.
 // The OriginCountryCode field should be set to the ISO 3166-1 country code of the originating country.
 // If Latinize is set to true, in countries where a latinized address format is provided, the latinized format is used.
+// See Formatter for the Transliterator and Registry fields.
 type PostalLabelFormatter struct {
 	Output            Outputter
 	OriginCountryCode string
 	Latinize          bool
+	Transliterator    Transliterator
+	Registry          *Registry
 }
 
 // Format formats an address. The language must be a valid ISO 639-1 language code. It is used to convert the keys
 // in administrative areas, localities and dependent localities into their actual names. If the provided language
-// does not have any translations, it falls back to the default language used by the country.
+// does not have any translations, it falls back to the default language used by the country. See Formatter for how
+// template errors are handled.
 func (f PostalLabelFormatter) Format(address Address, language string) string {
 
-	language = generated.normalizeLanguage(address.Country, language)
+	format, addressData, upper := f.prepare(address, language)
 
-	format, isLatinized := getFormat(address.Country, f.Latinize)
+	return mustFormatTemplate(f.Output, format, upper, addressData)
+}
 
-	countryData := generated.getCountry(address.Country)
+// FormatE formats an address in the same way as Format, but returns an error instead of panicking if the Outputter
+// produces an invalid template or the template fails to execute. The returned error wraps ErrInvalidTemplate.
+func (f PostalLabelFormatter) FormatE(address Address, language string) (string, error) {
 
-	addressData := address.toFormatData(countryData, language)
+	format, addressData, upper := f.prepare(address, language)
 
-	// Include the country since this is an international mail
-	if generated.hasCountry(f.OriginCountryCode) && strings.ToUpper(f.OriginCountryCode) != strings.ToUpper(address.Country) {
+	return formatTemplate(f.Output, format, upper, addressData)
+}
 
-		originLanguage, _ := textLanguage.Make(fmt.Sprintf("und-%s", f.OriginCountryCode)).Base()
+// FormatBilingual formats an address for a postal label using the country's local format and names of the subdivisions
+// in the language, as well as using the latinized format and the latinized names of the subdivisions. See
+// DefaultFormatter.FormatBilingual for details.
+func (f PostalLabelFormatter) FormatBilingual(address Address, language string) (BilingualAddress, error) {
 
-		destinationCountry := textLanguage.MustParseRegion(address.Country)
+	local := f
+	local.Latinize = false
 
-		oNamer := display.Regions(textLanguage.MustParse(originLanguage.String()))
-		namer := display.Regions(textLanguage.English)
+	latinized := f
+	latinized.Latinize = true
 
-		englishDestination := namer.Name(destinationCountry)
+	return formatBilingual(registryOrDefault(f.Registry), local, latinized, address, language)
+}
 
-		var translatedDestination string
+func formatBilingual(registry *Registry, local, latinized interface {
+	FormatE(address Address, language string) (string, error)
+}, address Address, language string) (BilingualAddress, error) {
 
-		if oNamer != nil {
-			translatedDestination = oNamer.Name(destinationCountry)
-		} else {
-			translatedDestination = englishDestination
-		}
+	localFormatted, err := local.FormatE(address, language)
+
+	if err != nil {
+		return BilingualAddress{}, err
+	}
+
+	latinizedFormatted, err := latinized.FormatE(address, registry.latinizedLanguage(address.Country, language))
+
+	if err != nil {
+		return BilingualAddress{}, err
+	}
+
+	return BilingualAddress{
+		Local:     localFormatted,
+		Latinized: latinizedFormatted,
+	}, nil
+}
+
+// FormatLines formats an address for a postal label into lines of tokens instead of a string, so that the layout can
+// be reused in places such as PDFs or native mobile user interfaces without losing which field each piece of text
+// came from. The Output field is not used.
+func (f PostalLabelFormatter) FormatLines(address Address, language string) [][]FormattedToken {
+
+	format, addressData, upper := f.prepare(address, language)
+
+	return layoutLinesToTokens(layoutFormat(format, addressData, upper))
+}
+
+// prepare returns the address format, the data to merge into it and the fields to uppercase for a postal label.
+func (f PostalLabelFormatter) prepare(address Address, language string) (string, formatData, map[Field]struct{}) {
+
+	registry := registryOrDefault(f.Registry)
+
+	language = registry.normalizeLanguage(address.Country, language)
+
+	format, isLatinized := registry.getFormat(address.Country, f.Latinize)
+
+	countryData := registry.getCountry(address.Country)
+
+	addressData := address.toFormatData(registry, countryData, language)
+
+	// Include the country since this is an international mail
+	if registry.hasCountry(f.OriginCountryCode) && strings.ToUpper(f.OriginCountryCode) != strings.ToUpper(address.Country) {
+
+		originLanguage, _ := textLanguage.Make(fmt.Sprintf("und-%s", f.OriginCountryCode)).Base()
+
+		englishDestination := registry.countryName(address.Country, "en")
+
+		translatedDestination := registry.countryName(address.Country, originLanguage.String())
 
 		if translatedDestination != englishDestination {
 			addressData.Country = fmt.Sprintf("%s - %s", strings.ToUpper(translatedDestination), strings.ToUpper(englishDestination))
@@ -125,15 +244,230 @@ func (f PostalLabelFormatter) Format(address Address, language string) string {
 		addressData.AdministrativeArea = addressData.AdministrativeAreaPostalKey
 	}
 
-	t := f.Output.TransformFormat(format, countryData.Upper)
+	return format, addressData.transliterate(f.Latinize, f.Transliterator), countryData.Upper
+}
 
-	compiled := template.Must(template.New("").Funcs(funcMap).Parse(t))
+// SingleLineFormatter formats an address on a single line, which is useful for receipts, search results and map pins.
+// The country's address format is followed, but lines are joined using a separator appropriate for the language
+// of the address and separators around empty fields are dropped. If Separator is set, it is used instead of the
+// language's separator. The name, organization and country can be left out by setting OmitName, OmitOrganization
+// and OmitCountry.
+// If Latinize is set to true, in countries where a latinized address format is provided, the latinized format is used.
+// See Formatter for the Transliterator and Registry fields.
+type SingleLineFormatter struct {
+	Separator        string
+	OmitName         bool
+	OmitOrganization bool
+	OmitCountry      bool
+	Latinize         bool
+	Transliterator   Transliterator
+	Registry         *Registry
+}
+
+// Format formats an address. The language must be a valid ISO 639-1 language code. It is used to convert the keys
+// in administrative areas, localities and dependent localities into their actual names. If the provided language
+// does not have any translations, it falls back to the default language used by the country.
+func (s SingleLineFormatter) Format(address Address, language string) string {
+
+	registry := registryOrDefault(s.Registry)
+
+	language = registry.normalizeLanguage(address.Country, language)
+
+	format, isLatinized := registry.getFormat(address.Country, s.Latinize)
+
+	if s.OmitName {
+		format = strings.ReplaceAll(format, "%N", "")
+	}
+
+	if s.OmitOrganization {
+		format = strings.ReplaceAll(format, "%O", "")
+	}
+
+	if !s.OmitCountry {
+		if isLatinized {
+			format += "%n%country"
+		} else {
+			format = "%country%n" + format
+		}
+	}
+
+	var lines []string
+
+	for _, line := range layoutFormat(format, address.toFormatData(registry, registry.getCountry(address.Country), language).transliterate(s.Latinize, s.Transliterator), map[Field]struct{}{}) {
+		if value := line.String(); value != "" {
+			lines = append(lines, value)
+		}
+	}
+
+	separator := s.Separator
+
+	if separator == "" {
+		separator = singleLineSeparator(language, isLatinized)
+	}
+
+	return strings.Join(lines, separator)
+}
+
+var singleLineSeparators = map[string]string{
+	"ar": "، ",
+	"fa": "، ",
+	"ja": " ",
+	"ko": " ",
+	"ur": "، ",
+	"zh": " ",
+}
+
+func singleLineSeparator(language string, isLatinized bool) string {
+
+	if !isLatinized {
+		base, _ := textLanguage.Make(language).Base()
+
+		if separator, ok := singleLineSeparators[base.String()]; ok {
+			return separator
+		}
+	}
+
+	return ", "
+}
+
+// ValidateOutputter checks that the templates produced by an Outputter can be parsed and executed. It is useful for
+// checking custom Outputters before using them with the formatters. The returned error wraps ErrInvalidTemplate.
+func ValidateOutputter(output Outputter) error {
+
+	if output == nil {
+		return fmt.Errorf("%w: no outputter", ErrInvalidTemplate)
+	}
+
+	format := "%country%n%N%n%O%n%A%n%D%n%C%n%S%n%Z%n%X"
+
+	data := formatData{
+		Country:                     "Country",
+		CountryEnglish:              "Country",
+		Name:                        "Name",
+		Organization:                "Organization",
+		StreetAddress:               []string{"Address Line 1", "Address Line 2"},
+		DependentLocality:           "Dependent Locality",
+		Locality:                    "Locality",
+		AdministrativeArea:          "Administrative Area",
+		AdministrativeAreaPostalKey: "AA",
+		PostCode:                    "Post Code",
+		SortingCode:                 "Sorting Code",
+	}
+
+	upper := map[Field]struct{}{}
+
+	for field := Country; field <= SortingCode; field++ {
+		upper[field] = struct{}{}
+	}
+
+	for _, u := range []map[Field]struct{}{{}, upper} {
+		if _, err := executeTemplate(output, output.TransformFormat(format, u), data); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// finalize collapses the whitespace and line breaks in a formatted address. Outputters in this package can further
+// process the result by implementing a finalize method.
+func finalize(output Outputter, formatted string) string {
+
+	formatted = collapseBRRegex.ReplaceAllString(collapseWhitespaceRegex.ReplaceAllString(strings.TrimSpace(formatted), "\n"), "<br>")
+
+	if f, ok := output.(interface{ finalize(string) string }); ok {
+		return f.finalize(formatted)
+	}
+
+	return formatted
+}
+
+// formatTemplate merges the address data into the template produced by the Outputter for a format. The returned
+// error wraps ErrInvalidTemplate.
+func formatTemplate(output Outputter, format string, upper map[Field]struct{}, data formatData) (string, error) {
+
+	if output == nil {
+		return "", fmt.Errorf("%w: no outputter", ErrInvalidTemplate)
+	}
+
+	formatted, err := executeTemplate(output, output.TransformFormat(format, upper), data)
+
+	if err != nil {
+		return "", err
+	}
+
+	return finalize(output, formatted), nil
+}
+
+// mustFormatTemplate merges the address data into the template produced by the Outputter for a format in the same way
+// as formatTemplate, but panics if the template cannot be parsed. Errors executing the template are ignored and the
+// output produced up to the error is returned.
+func mustFormatTemplate(output Outputter, format string, upper map[Field]struct{}, data formatData) string {
+
+	if output == nil {
+		panic(fmt.Errorf("%w: no outputter", ErrInvalidTemplate))
+	}
+
+	compiled, err := parseTemplate(output, output.TransformFormat(format, upper))
+
+	if err != nil {
+		panic(err)
+	}
 
 	buf := bytes.NewBuffer([]byte{})
 
-	compiled.Execute(buf, addressData)
+	_ = compiled.Execute(buf, data)
+
+	return finalize(output, buf.String())
+}
+
+func executeTemplate(output Outputter, t string, data formatData) (string, error) {
+
+	compiled, err := parseTemplate(output, t)
+
+	if err != nil {
+		return "", err
+	}
+
+	buf := bytes.NewBuffer([]byte{})
+
+	err = compiled.Execute(buf, data)
+
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", ErrInvalidTemplate, err)
+	}
+
+	return buf.String(), nil
+}
+
+// compiledTemplate is a template parsed by either html/template or text/template.
+type compiledTemplate interface {
+	Execute(wr io.Writer, data any) error
+}
+
+// parseTemplate parses the template produced by an Outputter. Templates produced by the plain text Outputters in this
+// package are parsed using text/template, so that the address fields are not HTML escaped. Templates produced by
+// other Outputters are parsed using html/template.
+func parseTemplate(output Outputter, t string) (compiledTemplate, error) {
+
+	if _, ok := output.(interface{ plainText() }); ok {
+
+		compiled, err := textTemplate.New("").Funcs(funcMap).Parse(t)
+
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidTemplate, err)
+		}
+
+		return compiled, nil
+	}
+
+	compiled, err := template.New("").Funcs(funcMap).Parse(t)
+
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidTemplate, err)
+	}
 
-	return collapseBRRegex.ReplaceAllString(collapseWhitespaceRegex.ReplaceAllString(strings.TrimSpace(buf.String()), "\n"), "<br>")
+	return compiled, nil
 }
 
 // Outputter defines an interface to transform an address format in Google's format into a Go template that is merged
@@ -166,6 +500,32 @@ func (h HTMLOutputter) TransformFormat(format string, upper map[Field]struct{})
 	return r.Replace(format)
 }
 
+// MicrodataOutputter outputs the formatted address as an HTML fragment in the same way as the HTMLOutputter, but the
+// address is also annotated using schema.org PostalAddress microdata, so that it is machine-readable.
+// The organization, dependent locality and sorting code do not have PostalAddress properties, so they are only
+// annotated using the class attribute.
+type MicrodataOutputter struct{}
+
+// TransformFormat transforms an address format in Google's format into a HTML template with microdata. The upper map
+// is used to determine which fields should be converted to UPPERCASE.
+func (m MicrodataOutputter) TransformFormat(format string, upper map[Field]struct{}) string {
+
+	r := strings.NewReplacer(
+		"%country", fmt.Sprintf(`{{if ne .%s "" }}<span class="country" itemprop="addressCountry">{{.%s}}</span>{{end}}`, Country, toUpper(Country, "", upper)),
+		"%N", fmt.Sprintf(`{{if ne .%s "" }}<span class="name" itemprop="name">{{.%s}}</span>{{end}}`, Name, toUpper(Name, "", upper)),
+		"%O", fmt.Sprintf(`{{if ne .%s "" }}<span class="organization">{{.%s}}</span>{{end}}`, Organization, toUpper(Organization, "", upper)),
+		"%A", fmt.Sprintf(`{{$numLines:=.%s|len}}{{range $lineNo, $line := .%s}}{{$realLineNo := inc $lineNo}}<span class="address-line-{{$realLineNo}}" itemprop="streetAddress">{{%s}}</span>{{if ne $numLines $realLineNo}}<br>{{end}}{{end}}`, StreetAddress, StreetAddress, toUpper(StreetAddress, "$line", upper)),
+		"%D", fmt.Sprintf(`{{if ne .%s "" }}<span class="dependent-locality">{{.%s}}</span>{{end}}`, DependentLocality, toUpper(DependentLocality, "", upper)),
+		"%C", fmt.Sprintf(`{{if ne .%s "" }}<span class="locality" itemprop="addressLocality">{{.%s}}</span>{{end}}`, Locality, toUpper(Locality, "", upper)),
+		"%S", fmt.Sprintf(`{{if ne .%s "" }}<span class="administrative-area" itemprop="addressRegion">{{.%s}}</span>{{end}}`, AdministrativeArea, toUpper(AdministrativeArea, "", upper)),
+		"%Z", fmt.Sprintf(`{{if ne .%s "" }}<span class="post-code" itemprop="postalCode">{{.%s}}</span>{{end}}`, PostCode, toUpper(PostCode, "", upper)),
+		"%X", fmt.Sprintf(`{{if ne .%s "" }}<span class="sorting-code">{{.%s}}</span>{{end}}`, SortingCode, toUpper(SortingCode, "", upper)),
+		"%n", "<br>",
+	)
+
+	return `<span itemscope itemtype="https://schema.org/PostalAddress">` + r.Replace(format) + `</span>`
+}
+
 // StringOutputter outputs the formatted address as a string and `\n`s are used for new lines.
 type StringOutputter struct{}
 
@@ -189,38 +549,136 @@ func (s StringOutputter) TransformFormat(format string, upper map[Field]struct{}
 	return r.Replace(format)
 }
 
-func toUpper(field Field, fieldName string, upperCaseFields map[Field]struct{}) string {
+func (s StringOutputter) plainText() {}
 
-	fieldToUse := field.String()
+// LabelledOutputter outputs the formatted address as labelled text, with each field on its own line, such as
+// `State: Victoria`. It is useful for support emails and audit logs. The labels of the administrative area,
+// locality, dependent locality and post code use the names used by the country (for example, PIN Code in India).
+// The fields are in the same order as the address format, but any text between the fields is left out.
+type LabelledOutputter struct{}
 
-	if fieldName != "" {
-		fieldToUse = fieldName
-	}
+// TransformFormat transforms an address format in Google's format into a labelled text template. The upper map is
+// used to determine which fields should be converted to UPPERCASE.
+func (l LabelledOutputter) TransformFormat(format string, upper map[Field]struct{}) string {
 
-	for upperField := range upperCaseFields {
-		if field == upperField {
-			return fmt.Sprintf("%s | toUpper", fieldToUse)
+	var lines []string
+
+	for _, formatLine := range strings.Split(format, "%n") {
+		for _, token := range tokenizeFormatLine(formatLine) {
+
+			switch token.Field {
+			case 0:
+				continue
+
+			case StreetAddress:
+				lines = append(lines, fmt.Sprintf(`{{range $line := .%s}}Street Address: {{%s}}`+"\n"+`{{end}}`, StreetAddress, toUpper(StreetAddress, "$line", upper)))
+
+			case DependentLocality, Locality, AdministrativeArea, PostCode:
+				lines = append(lines, fmt.Sprintf(`{{if ne .%s "" }}{{label .%sNameType}}: {{.%s}}{{end}}`, token.Field, token.Field, toUpper(token.Field, "", upper)))
+
+			default:
+				lines = append(lines, fmt.Sprintf(`{{if ne .%s "" }}%s: {{.%s}}{{end}}`, token.Field, fieldLabels[token.Field], toUpper(token.Field, "", upper)))
+			}
 		}
 	}
 
-	return fmt.Sprintf("%s", fieldToUse)
+	return strings.Join(lines, "\n")
+}
+
+func (l LabelledOutputter) plainText() {}
+
+var fieldLabels = map[Field]string{
+	Country:      "Country",
+	Name:         "Name",
+	Organization: "Organization",
+	SortingCode:  "Sorting Code",
+}
+
+var fieldNameLabels = map[FieldName]string{
+	DoSi:            "Do/Si",
+	PINCode:         "PIN Code",
+	PostTown:        "Post Town",
+	PostalCode:      "Postal Code",
+	VillageTownship: "Village/Township",
+	ZipCode:         "ZIP Code",
+}
+
+// fieldNameLabel returns the label of a field name for display, such as ZIP Code for ZipCode.
+func fieldNameLabel(fieldName FieldName) string {
+
+	if label, ok := fieldNameLabels[fieldName]; ok {
+		return label
+	}
+
+	return fieldName.String()
+}
+
+// MarkdownOutputter outputs the formatted address as Markdown. Markdown syntax in the address fields is escaped and
+// each line ends with a hard line break, so that the line breaks are kept when the Markdown is rendered.
+type MarkdownOutputter struct{}
+
+// TransformFormat transforms an address format in Google's format into a Markdown template. The upper map is used to
+// determine which fields should be converted to UPPERCASE.
+func (m MarkdownOutputter) TransformFormat(format string, upper map[Field]struct{}) string {
+
+	r := strings.NewReplacer(
+		"%country", fmt.Sprintf("{{.%s | markdown}}", toUpper(Country, "", upper)),
+		"%N", fmt.Sprintf("{{.%s | markdown}}", toUpper(Name, "", upper)),
+		"%O", fmt.Sprintf("{{.%s | markdown}}", toUpper(Organization, "", upper)),
+		"%A", fmt.Sprintf(`{{range $lineNo, $line := .%s}}{{if ne $lineNo 0}}`+"\n"+`{{end}}{{%s | markdown}}{{end}}`, StreetAddress, toUpper(StreetAddress, "$line", upper)),
+		"%D", fmt.Sprintf("{{.%s | markdown}}", toUpper(DependentLocality, "", upper)),
+		"%C", fmt.Sprintf("{{.%s | markdown}}", toUpper(Locality, "", upper)),
+		"%S", fmt.Sprintf("{{.%s | markdown}}", toUpper(AdministrativeArea, "", upper)),
+		"%Z", fmt.Sprintf("{{.%s | markdown}}", toUpper(PostCode, "", upper)),
+		"%X", fmt.Sprintf("{{.%s | markdown}}", toUpper(SortingCode, "", upper)),
+		"%n", "\n",
+	)
+
+	return r.Replace(format)
+}
+
+func (m MarkdownOutputter) plainText() {}
+
+// finalize adds a hard line break to the end of each line except the last.
+func (m MarkdownOutputter) finalize(formatted string) string {
+	return strings.ReplaceAll(formatted, "\n", "\\\n")
 }
 
-func getFormat(countryCode string, latinized bool) (string, bool) {
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	"`", "\\`",
+	`*`, `\*`,
+	`_`, `\_`,
+	`[`, `\[`,
+	`]`, `\]`,
+	`|`, `\|`,
+)
+
+// escapeMarkdown escapes characters that have a meaning in Markdown.
+func escapeMarkdown(value string) string {
 
-	countryData := generated.getCountry(countryCode)
+	value = markdownEscaper.Replace(value)
 
-	if latinized && countryData.LatinizedFormat != "" {
-		return countryData.LatinizedFormat, true
+	if strings.HasPrefix(value, "#") || strings.HasPrefix(value, "-") || strings.HasPrefix(value, "+") || strings.HasPrefix(value, ">") {
+		value = `\` + value
 	}
 
-	if countryData.Format != "" && countryData.LatinizedFormat == "" {
-		return countryData.Format, true
+	return value
+}
+
+func toUpper(field Field, fieldName string, upperCaseFields map[Field]struct{}) string {
+
+	fieldToUse := field.String()
+
+	if fieldName != "" {
+		fieldToUse = fieldName
 	}
 
-	if countryData.Format != "" {
-		return countryData.Format, false
+	for upperField := range upperCaseFields {
+		if field == upperField {
+			return fmt.Sprintf("%s | toUpper", fieldToUse)
+		}
 	}
 
-	return generated.getCountry("ZZ").Format, true
+	return fmt.Sprintf("%s", fieldToUse)
 }
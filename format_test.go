@@ -1,6 +1,7 @@
 package address
 
 import (
+	"errors"
 	"testing"
 )
 
@@ -104,6 +105,20 @@ func TestDefaultFormatter(t *testing.T) {
 			},
 			Expected: "中国\n677000\n云南省临沧市\n1 西河北路\n星巴克\n司馬遷",
 		},
+		{
+			Address: []func(*Address){
+				WithName("Tom O'Brien"),
+				WithOrganization("O'Brien & Co"),
+				WithStreetAddress([]string{
+					"525 <Collins> Street",
+				}),
+				WithLocality("Melbourne"),
+				WithAdministrativeArea("VIC"),
+				WithPostCode("3000"),
+				WithCountry("AU"),
+			},
+			Expected: "O'Brien & Co\nTom O'Brien\n525 <Collins> Street\nMelbourne Victoria 3000\nAustralia",
+		},
 	}
 
 	for i, testCase := range testCases {
@@ -763,3 +778,676 @@ func TestPostalLabelFormatterForInternationalMailWithoutValidOriginCountry(t *te
 		}
 	}
 }
+
+func TestSingleLineFormatter(t *testing.T) {
+
+	testCases := []struct {
+		Formatter SingleLineFormatter
+		Address   []func(*Address)
+		Expected  string
+	}{
+		{
+			Formatter: SingleLineFormatter{},
+			Address: []func(*Address){
+				WithName("John Smith"),
+				WithOrganization("Company Pty Ltd"),
+				WithStreetAddress([]string{
+					"Suite 7, 9th Floor",
+					"525 Collins Street",
+				}),
+				WithLocality("Melbourne"),
+				WithAdministrativeArea("VIC"),
+				WithPostCode("3000"),
+				WithCountry("AU"),
+			},
+			Expected: "Company Pty Ltd, John Smith, Suite 7, 9th Floor, 525 Collins Street, Melbourne Victoria 3000, Australia",
+		},
+		{
+			Formatter: SingleLineFormatter{
+				OmitName:         true,
+				OmitOrganization: true,
+				OmitCountry:      true,
+			},
+			Address: []func(*Address){
+				WithName("John Smith"),
+				WithOrganization("Microsoft"),
+				WithStreetAddress([]string{
+					"1 Microsoft Way",
+				}),
+				WithLocality("Redmond"),
+				WithAdministrativeArea("WA"),
+				WithPostCode("98052"),
+				WithCountry("US"),
+			},
+			Expected: "1 Microsoft Way, Redmond, Washington 98052",
+		},
+		{
+			Formatter: SingleLineFormatter{
+				OmitCountry: true,
+			},
+			Address: []func(*Address){
+				WithStreetAddress([]string{
+					"1 Microsoft Way",
+				}),
+				WithAdministrativeArea("WA"),
+				WithPostCode("98052"),
+				WithCountry("US"),
+			},
+			Expected: "1 Microsoft Way, Washington 98052",
+		},
+		{
+			Formatter: SingleLineFormatter{
+				Separator: " | ",
+			},
+			Address: []func(*Address){
+				WithName("Walter C. Brown"),
+				WithStreetAddress([]string{
+					"49 Featherstone Street",
+				}),
+				WithLocality("London"),
+				WithPostCode("EC1Y 8SY"),
+				WithCountry("GB"),
+			},
+			Expected: "Walter C. Brown | 49 Featherstone Street | London | EC1Y 8SY | United Kingdom",
+		},
+		{
+			Formatter: SingleLineFormatter{},
+			Address: []func(*Address){
+				WithName("司馬遷"),
+				WithStreetAddress([]string{
+					"1 西河北路",
+				}),
+				WithLocality("临沧市"),
+				WithAdministrativeArea("53"),
+				WithPostCode("677000"),
+				WithCountry("CN"),
+			},
+			Expected: "中国 677000 云南省临沧市 1 西河北路 司馬遷",
+		},
+		{
+			Formatter: SingleLineFormatter{
+				Latinize: true,
+			},
+			Address: []func(*Address){
+				WithName("司馬遷"),
+				WithStreetAddress([]string{
+					"1 西河北路",
+				}),
+				WithLocality("临沧市"),
+				WithAdministrativeArea("53"),
+				WithPostCode("677000"),
+				WithCountry("CN"),
+			},
+			Expected: "司馬遷, 1 西河北路, 临沧市, 云南省, 677000, 中国",
+		},
+		{
+			Formatter: SingleLineFormatter{
+				OmitCountry: true,
+			},
+			Address: []func(*Address){
+				WithName("Tom O'Brien"),
+				WithOrganization("O'Brien & Co"),
+				WithStreetAddress([]string{
+					"525 <Collins> Street",
+				}),
+				WithLocality("Melbourne"),
+				WithAdministrativeArea("VIC"),
+				WithPostCode("3000"),
+				WithCountry("AU"),
+			},
+			Expected: "O'Brien & Co, Tom O'Brien, 525 <Collins> Street, Melbourne Victoria 3000",
+		},
+		{
+			Formatter: SingleLineFormatter{},
+			Address: []func(*Address){
+				WithName("山田"),
+				WithStreetAddress([]string{
+					"1-2-3",
+				}),
+				WithAdministrativeArea("13"),
+				WithCountry("JP"),
+			},
+			Expected: "日本 東京都 1-2-3 山田",
+		},
+		{
+			Formatter: SingleLineFormatter{},
+			Address: []func(*Address){
+				WithStreetAddress([]string{
+					"Bahnhofstrasse 1",
+				}),
+				WithLocality("Zürich"),
+				WithCountry("CH"),
+			},
+			Expected: "Bahnhofstrasse 1, Zürich, Schweiz",
+		},
+	}
+
+	for i, testCase := range testCases {
+
+		address := New(testCase.Address...)
+
+		formatted := testCase.Formatter.Format(address, "")
+
+		if formatted != testCase.Expected {
+			t.Errorf("Formatted address for test case %d does not match the expected result, got %q", i, formatted)
+		}
+	}
+}
+
+func TestFormatLines(t *testing.T) {
+
+	validAddress, err := NewValid(
+		WithName("John Smith"),
+		WithStreetAddress([]string{
+			"Suite 7, 9th Floor",
+			"525 Collins Street",
+		}),
+		WithLocality("Melbourne"),
+		WithAdministrativeArea("VIC"),
+		WithPostCode("3000"),
+		WithCountry("AU"),
+	)
+
+	if err != nil {
+		t.Fatalf("Error creating valid address: %s", err)
+	}
+
+	testCases := []struct {
+		Formatter interface {
+			FormatLines(Address, string) [][]FormattedToken
+		}
+		Address  Address
+		Expected [][]FormattedToken
+	}{
+		{
+			Formatter: DefaultFormatter{},
+			Expected: [][]FormattedToken{
+				{{Field: Name, Value: "John Smith"}},
+				{{Field: StreetAddress, Value: "Suite 7, 9th Floor"}},
+				{{Field: StreetAddress, Value: "525 Collins Street"}},
+				{{Field: Locality, Value: "Melbourne"}, {Value: " "}, {Field: AdministrativeArea, Value: "Victoria"}, {Value: " "}, {Field: PostCode, Value: "3000"}},
+				{{Field: Country, Value: "Australia"}},
+			},
+		},
+		{
+			Formatter: PostalLabelFormatter{
+				OriginCountryCode: "FR",
+			},
+			Expected: [][]FormattedToken{
+				{{Field: Name, Value: "John Smith"}},
+				{{Field: StreetAddress, Value: "Suite 7, 9th Floor"}},
+				{{Field: StreetAddress, Value: "525 Collins Street"}},
+				{{Field: Locality, Value: "MELBOURNE", Upper: true}, {Value: " "}, {Field: AdministrativeArea, Value: "VIC", Upper: true}, {Value: " "}, {Field: PostCode, Value: "3000"}},
+				{{Field: Country, Value: "AUSTRALIE - AUSTRALIA"}},
+			},
+		},
+		{
+			Formatter: DefaultFormatter{},
+			Address:   New(WithStreetAddress([]string{"1 Main Street"}), WithLocality("Springfield"), WithPostCode("12345"), WithCountry("US")),
+			Expected: [][]FormattedToken{
+				{{Field: StreetAddress, Value: "1 Main Street"}},
+				{{Field: Locality, Value: "Springfield"}, {Value: ", "}, {Field: PostCode, Value: "12345"}},
+				{{Field: Country, Value: "United States"}},
+			},
+		},
+		{
+			Formatter: DefaultFormatter{},
+			Address:   New(WithStreetAddress([]string{"Bahnhofstrasse 1"}), WithLocality("Zürich"), WithCountry("CH")),
+			Expected: [][]FormattedToken{
+				{{Field: StreetAddress, Value: "Bahnhofstrasse 1"}},
+				{{Field: Locality, Value: "Zürich"}},
+				{{Field: Country, Value: "Schweiz"}},
+			},
+		},
+		{
+			Formatter: DefaultFormatter{},
+			Address:   New(WithStreetAddress([]string{"1 Calle"}), WithLocality("San Juan"), WithPostCode("00930"), WithCountry("PR")),
+			Expected: [][]FormattedToken{
+				{{Field: StreetAddress, Value: "1 Calle"}},
+				{{Field: Locality, Value: "San Juan"}, {Value: " PR "}, {Field: PostCode, Value: "00930"}},
+				{{Field: Country, Value: "Puerto Rico"}},
+			},
+		},
+	}
+
+	for i, testCase := range testCases {
+
+		address := testCase.Address
+
+		if address.Country == "" {
+			address = validAddress
+		}
+
+		lines := testCase.Formatter.FormatLines(address, "en")
+
+		if len(lines) != len(testCase.Expected) {
+			t.Fatalf("Expected %d lines for test case %d, got %d: %v", len(testCase.Expected), i, len(lines), lines)
+		}
+
+		for j, line := range lines {
+
+			if len(line) != len(testCase.Expected[j]) {
+				t.Fatalf("Expected %d tokens on line %d for test case %d, got %d: %v", len(testCase.Expected[j]), j, i, len(line), line)
+			}
+
+			for k, token := range line {
+				if token != testCase.Expected[j][k] {
+					t.Errorf("Expected token %d on line %d for test case %d to be %v, got %v", k, j, i, testCase.Expected[j][k], token)
+				}
+			}
+		}
+	}
+}
+
+type invalidOutputter struct{}
+
+func (i invalidOutputter) TransformFormat(format string, upper map[Field]struct{}) string {
+	return "{{.Name"
+}
+
+type missingFieldOutputter struct{}
+
+func (m missingFieldOutputter) TransformFormat(format string, upper map[Field]struct{}) string {
+	return "{{.Street}}"
+}
+
+func TestFormatE(t *testing.T) {
+
+	address, err := NewValid(
+		WithName("John Smith"),
+		WithStreetAddress([]string{
+			"525 Collins Street",
+		}),
+		WithLocality("Melbourne"),
+		WithAdministrativeArea("VIC"),
+		WithPostCode("3000"),
+		WithCountry("AU"),
+	)
+
+	if err != nil {
+		t.Fatalf("Error creating valid address: %s", err)
+	}
+
+	testCases := []struct {
+		Formatter interface {
+			FormatE(Address, string) (string, error)
+		}
+		Expected    string
+		ExpectedErr error
+	}{
+		{
+			Formatter: DefaultFormatter{Output: StringOutputter{}},
+			Expected:  "John Smith\n525 Collins Street\nMelbourne Victoria 3000\nAustralia",
+		},
+		{
+			Formatter:   DefaultFormatter{Output: invalidOutputter{}},
+			ExpectedErr: ErrInvalidTemplate,
+		},
+		{
+			Formatter:   DefaultFormatter{Output: missingFieldOutputter{}},
+			ExpectedErr: ErrInvalidTemplate,
+		},
+		{
+			Formatter:   DefaultFormatter{},
+			ExpectedErr: ErrInvalidTemplate,
+		},
+		{
+			Formatter: PostalLabelFormatter{Output: StringOutputter{}, OriginCountryCode: "AU"},
+			Expected:  "John Smith\n525 Collins Street\nMELBOURNE VIC 3000",
+		},
+		{
+			Formatter:   PostalLabelFormatter{Output: invalidOutputter{}},
+			ExpectedErr: ErrInvalidTemplate,
+		},
+	}
+
+	for i, testCase := range testCases {
+
+		formatted, err := testCase.Formatter.FormatE(address, "")
+
+		if !errors.Is(err, testCase.ExpectedErr) {
+			t.Errorf("Expected error %v for test case %d, got %v", testCase.ExpectedErr, i, err)
+		}
+
+		if formatted != testCase.Expected {
+			t.Errorf("Formatted address for test case %d does not match the expected result, got %q", i, formatted)
+		}
+	}
+}
+
+type unknownFieldOutputter struct{}
+
+func (u unknownFieldOutputter) TransformFormat(format string, upper map[Field]struct{}) string {
+	return "{{.Name}}\n{{.Nope}}"
+}
+
+func TestFormatWithInvalidTemplate(t *testing.T) {
+
+	address, err := NewValid(
+		WithName("John Smith"),
+		WithStreetAddress([]string{
+			"525 Collins Street",
+		}),
+		WithLocality("Melbourne"),
+		WithAdministrativeArea("VIC"),
+		WithPostCode("3000"),
+		WithCountry("AU"),
+	)
+
+	if err != nil {
+		t.Fatalf("Error creating valid address: %s", err)
+	}
+
+	testCases := []struct {
+		Formatter     Formatter
+		Expected      string
+		ExpectedPanic bool
+	}{
+		{
+			Formatter: DefaultFormatter{Output: unknownFieldOutputter{}},
+			Expected:  "John Smith",
+		},
+		{
+			Formatter: PostalLabelFormatter{Output: unknownFieldOutputter{}, OriginCountryCode: "AU"},
+			Expected:  "John Smith",
+		},
+		{
+			Formatter: MilitaryFormatter{Output: unknownFieldOutputter{}},
+			Expected:  "John Smith",
+		},
+		{
+			Formatter:     DefaultFormatter{Output: invalidOutputter{}},
+			ExpectedPanic: true,
+		},
+		{
+			Formatter:     PostalLabelFormatter{Output: invalidOutputter{}},
+			ExpectedPanic: true,
+		},
+	}
+
+	for i, testCase := range testCases {
+
+		func() {
+
+			defer func() {
+				if r := recover(); (r != nil) != testCase.ExpectedPanic {
+					t.Errorf("Expected panic for test case %d to be %t, got %v", i, testCase.ExpectedPanic, r)
+				}
+			}()
+
+			if formatted := testCase.Formatter.Format(address, ""); formatted != testCase.Expected {
+				t.Errorf("Formatted address for test case %d does not match the expected result, got %q", i, formatted)
+			}
+		}()
+	}
+}
+
+func TestValidateOutputter(t *testing.T) {
+
+	testCases := []struct {
+		Outputter   Outputter
+		ExpectedErr error
+	}{
+		{
+			Outputter: StringOutputter{},
+		},
+		{
+			Outputter: HTMLOutputter{},
+		},
+		{
+			Outputter:   invalidOutputter{},
+			ExpectedErr: ErrInvalidTemplate,
+		},
+		{
+			Outputter:   missingFieldOutputter{},
+			ExpectedErr: ErrInvalidTemplate,
+		},
+		{
+			Outputter:   nil,
+			ExpectedErr: ErrInvalidTemplate,
+		},
+	}
+
+	for i, testCase := range testCases {
+
+		err := ValidateOutputter(testCase.Outputter)
+
+		if !errors.Is(err, testCase.ExpectedErr) {
+			t.Errorf("Expected error %v for test case %d, got %v", testCase.ExpectedErr, i, err)
+		}
+	}
+}
+
+func TestMicrodataOutputter(t *testing.T) {
+
+	f := DefaultFormatter{
+		Output: MicrodataOutputter{},
+	}
+
+	address, err := NewValid(
+		WithName("John Smith"),
+		WithOrganization("Company Pty Ltd"),
+		WithStreetAddress([]string{
+			"525 Collins Street",
+		}),
+		WithLocality("Melbourne"),
+		WithAdministrativeArea("VIC"),
+		WithPostCode("3000"),
+		WithCountry("AU"),
+	)
+
+	if err != nil {
+		t.Fatalf("Error creating valid address: %s", err)
+	}
+
+	expected := `<span itemscope itemtype="https://schema.org/PostalAddress"><span class="organization">Company Pty Ltd</span><br><span class="name" itemprop="name">John Smith</span><br><span class="address-line-1" itemprop="streetAddress">525 Collins Street</span><br><span class="locality" itemprop="addressLocality">Melbourne</span> <span class="administrative-area" itemprop="addressRegion">Victoria</span> <span class="post-code" itemprop="postalCode">3000</span><br><span class="country" itemprop="addressCountry">Australia</span></span>`
+
+	if formatted := f.Format(address, "en"); formatted != expected {
+		t.Errorf("Formatted address does not match the expected result, got %s", formatted)
+	}
+}
+
+func TestLabelledOutputter(t *testing.T) {
+
+	testCases := []struct {
+		Address   Address
+		Formatter Formatter
+		Expected  string
+	}{
+		{
+			Address: New(
+				WithName("John Smith"),
+				WithOrganization("Company Pty Ltd"),
+				WithStreetAddress([]string{
+					"Level 1",
+					"525 Collins Street",
+				}),
+				WithLocality("Melbourne"),
+				WithAdministrativeArea("VIC"),
+				WithPostCode("3000"),
+				WithCountry("AU"),
+			),
+			Formatter: DefaultFormatter{
+				Output: LabelledOutputter{},
+			},
+			Expected: "Organization: Company Pty Ltd\nName: John Smith\nStreet Address: Level 1\nStreet Address: 525 Collins Street\nSuburb: Melbourne\nState: Victoria\nPostal Code: 3000\nCountry: Australia",
+		},
+		{
+			Address: New(
+				WithStreetAddress([]string{
+					"1 Microsoft Way",
+				}),
+				WithLocality("Redmond"),
+				WithAdministrativeArea("WA"),
+				WithPostCode("98052"),
+				WithCountry("US"),
+			),
+			Formatter: PostalLabelFormatter{
+				Output:            LabelledOutputter{},
+				OriginCountryCode: "US",
+			},
+			Expected: "Street Address: 1 Microsoft Way\nCity: REDMOND\nState: WA\nZIP Code: 98052",
+		},
+		{
+			Address: New(
+				WithName("Tom O'Brien"),
+				WithOrganization("O'Brien & Co"),
+				WithStreetAddress([]string{
+					"525 <Collins> Street",
+				}),
+				WithLocality("Melbourne"),
+				WithAdministrativeArea("VIC"),
+				WithPostCode("3000"),
+				WithCountry("AU"),
+			),
+			Formatter: DefaultFormatter{
+				Output: LabelledOutputter{},
+			},
+			Expected: "Organization: O'Brien & Co\nName: Tom O'Brien\nStreet Address: 525 <Collins> Street\nSuburb: Melbourne\nState: Victoria\nPostal Code: 3000\nCountry: Australia",
+		},
+	}
+
+	for i, testCase := range testCases {
+		if formatted := testCase.Formatter.Format(testCase.Address, "en"); formatted != testCase.Expected {
+			t.Errorf("Formatted address for test case %d does not match the expected result, got %q", i, formatted)
+		}
+	}
+}
+
+func TestMarkdownOutputter(t *testing.T) {
+
+	f := DefaultFormatter{
+		Output: MarkdownOutputter{},
+	}
+
+	address := New(
+		WithName("John *Smith*"),
+		WithOrganization("O'Brien & Co"),
+		WithStreetAddress([]string{
+			"# 12_3 [Collins] <Street>",
+		}),
+		WithLocality("Melbourne"),
+		WithAdministrativeArea("VIC"),
+		WithPostCode("3000"),
+		WithCountry("AU"),
+	)
+
+	expected := "O'Brien & Co\\\nJohn \\*Smith\\*\\\n\\# 12\\_3 \\[Collins\\] <Street>\\\nMelbourne Victoria 3000\\\nAustralia"
+
+	if formatted := f.Format(address, "en"); formatted != expected {
+		t.Errorf("Formatted address does not match the expected result, got %q", formatted)
+	}
+}
+
+func TestFormatBilingual(t *testing.T) {
+
+	testCases := []struct {
+		Address   Address
+		Formatter interface {
+			FormatBilingual(address Address, language string) (BilingualAddress, error)
+		}
+		Expected BilingualAddress
+	}{
+		{
+			Address: New(
+				WithName("John Smith"),
+				WithStreetAddress([]string{
+					"1 Main Street",
+				}),
+				WithLocality("东城区"),
+				WithAdministrativeArea("11"),
+				WithPostCode("100000"),
+				WithCountry("CN"),
+			),
+			Formatter: PostalLabelFormatter{
+				Output:            StringOutputter{},
+				OriginCountryCode: "US",
+			},
+			Expected: BilingualAddress{
+				Local:     "CHINA\n100000\n北京市东城区\n1 Main Street\nJohn Smith",
+				Latinized: "John Smith\n1 Main Street\nDongcheng Qu\nBEIJING SHI, 100000\nCHINA",
+			},
+		},
+		{
+			Address: New(
+				WithName("John Smith"),
+				WithStreetAddress([]string{
+					"1-1",
+				}),
+				WithAdministrativeArea("13"),
+				WithPostCode("100-0001"),
+				WithCountry("JP"),
+			),
+			Formatter: DefaultFormatter{
+				Output:   StringOutputter{},
+				Latinize: true,
+			},
+			Expected: BilingualAddress{
+				Local:     "日本\n〒100-0001\n東京都\n1-1\nJohn Smith",
+				Latinized: "John Smith\n1-1, Tokyo\n100-0001\nJapan",
+			},
+		},
+	}
+
+	for i, testCase := range testCases {
+
+		formatted, err := testCase.Formatter.FormatBilingual(testCase.Address, "ja")
+
+		if err != nil {
+			t.Errorf("Unexpected error formatting test case %d: %s", i, err)
+			continue
+		}
+
+		if formatted != testCase.Expected {
+			t.Errorf("Formatted address for test case %d does not match the expected result, got %#v", i, formatted)
+		}
+	}
+}
+
+func TestTransliteration(t *testing.T) {
+
+	address := New(
+		WithName("Иван Петров"),
+		WithStreetAddress([]string{
+			"ул. Тверская, д. 7",
+		}),
+		WithLocality("Москва"),
+		WithAdministrativeArea("Москва"),
+		WithPostCode("125009"),
+		WithCountry("RU"),
+	)
+
+	testCases := []struct {
+		Formatter Formatter
+		Expected  string
+	}{
+		{
+			Formatter: DefaultFormatter{
+				Output:         StringOutputter{},
+				Latinize:       true,
+				Transliterator: DefaultTransliterator,
+			},
+			Expected: "Ivan Petrov\nul. Tverskaia, d. 7\nMoskva\nMoskva\n125009\nRussia",
+		},
+		{
+			Formatter: DefaultFormatter{
+				Output:         StringOutputter{},
+				Transliterator: DefaultTransliterator,
+			},
+			Expected: "Russia\nИван Петров\nул. Тверская, д. 7\nМосква\nМосква\n125009",
+		},
+		{
+			Formatter: SingleLineFormatter{
+				Latinize:       true,
+				Transliterator: DefaultTransliterator,
+			},
+			Expected: "Ivan Petrov, ul. Tverskaia, d. 7, Moskva, Moskva, 125009, Russia",
+		},
+	}
+
+	for i, testCase := range testCases {
+		if formatted := testCase.Formatter.Format(address, "en"); formatted != testCase.Expected {
+			t.Errorf("Formatted address for test case %d does not match the expected result, got %q", i, formatted)
+		}
+	}
+}
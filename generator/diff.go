@@ -0,0 +1,663 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"regexp/syntax"
+	"slices"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/Boostport/address"
+	"github.com/Boostport/address/internal/addressdata"
+)
+
+const defaultSamples = 5
+
+// maxExpansion limits the number of strings generated for each part of a post code regex when generating samples.
+const maxExpansion = 64
+
+// countryChanges contains the changes to a country between two datasets.
+type countryChanges struct {
+	Code    string
+	Name    string
+	Changes []change
+}
+
+// change describes a change to a country. The details, such as sample post codes, are optional.
+type change struct {
+	Description string
+	Details     []string
+}
+
+// runDiff runs the diff subcommand, which compares two datasets and prints a report of the changes to each country.
+// Each dataset is either a file of encoded data, such as data.generated.bin, or a snapshot directory.
+func runDiff(args []string) error {
+
+	flags := flag.NewFlagSet("diff", flag.ExitOnError)
+
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: generator diff [flags] <old> <new>\n\n")
+		fmt.Fprintf(flags.Output(), "Compares two datasets, each of which is a file of encoded data such as data.generated.bin or a\n")
+		fmt.Fprintf(flags.Output(), "snapshot directory, and prints the changes to each country.\n\n")
+		flags.PrintDefaults()
+	}
+
+	samplesFlag := flags.Int("samples", defaultSamples, "maximum number of sample post codes to show for each post code regex change")
+	concurrencyFlag := flags.Int("concurrency", defaultConcurrency, "maximum number of countries to process at the same time when reading a snapshot")
+	flags.Parse(args)
+
+	if flags.NArg() != 2 {
+		flags.Usage()
+		return fmt.Errorf("expected 2 datasets, got %d", flags.NArg())
+	}
+
+	// Keep the report on stdout free of the progress of reading snapshots
+	progress = os.Stderr
+
+	oldCountries, err := loadDataset(flags.Arg(0), *concurrencyFlag)
+
+	if err != nil {
+		return fmt.Errorf("error loading %s: %s", flags.Arg(0), err)
+	}
+
+	newCountries, err := loadDataset(flags.Arg(1), *concurrencyFlag)
+
+	if err != nil {
+		return fmt.Errorf("error loading %s: %s", flags.Arg(1), err)
+	}
+
+	return writeReport(os.Stdout, diffData(oldCountries, newCountries, *samplesFlag))
+}
+
+// loadDataset loads a file of encoded data or processes the data in a snapshot directory.
+func loadDataset(path string, concurrency int) (map[string]country, error) {
+
+	info, err := os.Stat(path)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if info.IsDir() {
+		countries, _, err := generate(&snapshotFetcher{dir: path}, "", concurrency)
+		return countries, err
+	}
+
+	encoded, err := os.ReadFile(path)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return addressdata.Decode(encoded)
+}
+
+// diffData compares two datasets and returns the changes to each country, sorted by country code. At most samples
+// sample post codes are included for each post code regex change.
+func diffData(oldCountries map[string]country, newCountries map[string]country, samples int) []countryChanges {
+
+	codes := sortedKeys(oldCountries)
+
+	for code := range newCountries {
+		if _, ok := oldCountries[code]; !ok {
+			codes = append(codes, code)
+		}
+	}
+
+	sort.Strings(codes)
+
+	var report []countryChanges
+
+	for _, code := range codes {
+
+		oldCountry, inOld := oldCountries[code]
+		newCountry, inNew := newCountries[code]
+
+		switch {
+		case !inOld:
+			report = append(report, countryChanges{
+				Code:    code,
+				Name:    newCountry.Name,
+				Changes: []change{{Description: "Country added"}},
+			})
+
+		case !inNew:
+			report = append(report, countryChanges{
+				Code:    code,
+				Name:    oldCountry.Name,
+				Changes: []change{{Description: "Country removed"}},
+			})
+
+		default:
+			if changes := diffCountry(oldCountry, newCountry, samples); len(changes) > 0 {
+				report = append(report, countryChanges{
+					Code:    code,
+					Name:    newCountry.Name,
+					Changes: changes,
+				})
+			}
+		}
+	}
+
+	return report
+}
+
+func diffCountry(oldCountry country, newCountry country, samples int) []change {
+
+	var changes []change
+
+	changed := func(what string, oldValue string, newValue string) {
+		if oldValue != newValue {
+			changes = append(changes, change{
+				Description: fmt.Sprintf("%s changed from %q to %q", what, oldValue, newValue),
+			})
+		}
+	}
+
+	changed("Name", oldCountry.Name, newCountry.Name)
+	changed("Default language", oldCountry.DefaultLanguage, newCountry.DefaultLanguage)
+	changed("Format", oldCountry.Format, newCountry.Format)
+	changed("Latinized format", oldCountry.LatinizedFormat, newCountry.LatinizedFormat)
+	changed("Post code prefix", oldCountry.PostCodePrefix, newCountry.PostCodePrefix)
+	changed("Post office URL", oldCountry.PostURL, newCountry.PostURL)
+	changed("Administrative area name type", address.FieldName(oldCountry.AdministrativeAreaNameType).String(), address.FieldName(newCountry.AdministrativeAreaNameType).String())
+	changed("Locality name type", address.FieldName(oldCountry.LocalityNameType).String(), address.FieldName(newCountry.LocalityNameType).String())
+	changed("Dependent locality name type", address.FieldName(oldCountry.DependentLocalityNameType).String(), address.FieldName(newCountry.DependentLocalityNameType).String())
+	changed("Post code name type", address.FieldName(oldCountry.PostCodeNameType).String(), address.FieldName(newCountry.PostCodeNameType).String())
+
+	changes = append(changes, diffFields("Required fields", oldCountry.RequiredFields, newCountry.RequiredFields)...)
+	changes = append(changes, diffFields("Allowed fields", oldCountry.AllowedFields, newCountry.AllowedFields)...)
+	changes = append(changes, diffFields("Uppercase fields", oldCountry.Upper, newCountry.Upper)...)
+
+	changes = append(changes, diffSubdivisions(oldCountry.AdministrativeAreas, newCountry.AdministrativeAreas)...)
+	changes = append(changes, diffRedirects(oldCountry.AdministrativeAreaRedirects, newCountry.AdministrativeAreaRedirects)...)
+	changes = append(changes, diffPostCodeRegexes(oldCountry.PostCodeRegex, newCountry.PostCodeRegex, samples)...)
+
+	return changes
+}
+
+func diffFields(what string, oldFields addressdata.FieldSet, newFields addressdata.FieldSet) []change {
+
+	var changes []change
+
+	if added := missingFields(newFields, oldFields); len(added) > 0 {
+		changes = append(changes, change{
+			Description: fmt.Sprintf("%s added: %s", what, strings.Join(added, ", ")),
+		})
+	}
+
+	if removed := missingFields(oldFields, newFields); len(removed) > 0 {
+		changes = append(changes, change{
+			Description: fmt.Sprintf("%s removed: %s", what, strings.Join(removed, ", ")),
+		})
+	}
+
+	return changes
+}
+
+// missingFields returns the names of the fields in a that are not in b, in the order of the fields.
+func missingFields(a addressdata.FieldSet, b addressdata.FieldSet) []string {
+
+	var names []string
+
+	for field := address.Country; field <= address.SortingCode; field++ {
+		if a&(1<<field) != 0 && b&(1<<field) == 0 {
+			names = append(names, field.String())
+		}
+	}
+
+	return names
+}
+
+// subdivision is an administrative area, locality or dependent locality, identified by the path of IDs from its
+// administrative area, for example CN-11/东城区.
+type subdivision struct {
+	kind          string
+	path          string
+	name          string
+	postalKey     string
+	isoID         string
+	latinizedName string
+}
+
+func flattenSubdivisions(adminAreas []administrativeArea) []subdivision {
+
+	var subdivisions []subdivision
+
+	for _, adminArea := range adminAreas {
+
+		subdivisions = append(subdivisions, subdivision{
+			kind:          "Administrative area",
+			path:          adminArea.ID,
+			name:          adminArea.Name,
+			postalKey:     adminArea.PostalKey,
+			isoID:         adminArea.ISOID,
+			latinizedName: adminArea.LatinizedName,
+		})
+
+		for _, locality := range adminArea.Localities {
+
+			localityPath := adminArea.ID + "/" + locality.ID
+
+			subdivisions = append(subdivisions, subdivision{
+				kind:          "Locality",
+				path:          localityPath,
+				name:          locality.Name,
+				latinizedName: locality.LatinizedName,
+			})
+
+			for _, dependentLocality := range locality.DependentLocalities {
+				subdivisions = append(subdivisions, subdivision{
+					kind:          "Dependent locality",
+					path:          localityPath + "/" + dependentLocality.ID,
+					name:          dependentLocality.Name,
+					latinizedName: dependentLocality.LatinizedName,
+				})
+			}
+		}
+	}
+
+	return subdivisions
+}
+
+func diffSubdivisions(oldAdminAreas map[string][]administrativeArea, newAdminAreas map[string][]administrativeArea) []change {
+
+	languages := sortedKeys(oldAdminAreas)
+
+	for language := range newAdminAreas {
+		if _, ok := oldAdminAreas[language]; !ok {
+			languages = append(languages, language)
+		}
+	}
+
+	sort.Strings(languages)
+
+	var changes []change
+
+	for _, language := range languages {
+
+		oldSubdivisions := flattenSubdivisions(oldAdminAreas[language])
+		newSubdivisions := flattenSubdivisions(newAdminAreas[language])
+
+		oldByPath := map[string]subdivision{}
+		newByPath := map[string]subdivision{}
+
+		for _, s := range oldSubdivisions {
+			oldByPath[s.path] = s
+		}
+
+		for _, s := range newSubdivisions {
+			newByPath[s.path] = s
+		}
+
+		for _, s := range newSubdivisions {
+
+			old, ok := oldByPath[s.path]
+
+			if !ok {
+				changes = append(changes, change{
+					Description: fmt.Sprintf("%s added [%s]: %s (%s)", s.kind, language, s.path, s.name),
+				})
+				continue
+			}
+
+			if old.name != s.name {
+				changes = append(changes, change{
+					Description: fmt.Sprintf("%s renamed [%s]: %s from %q to %q", s.kind, language, s.path, old.name, s.name),
+				})
+			}
+
+			if old.postalKey != s.postalKey {
+				changes = append(changes, change{
+					Description: fmt.Sprintf("%s postal key changed [%s]: %s from %q to %q", s.kind, language, s.path, old.postalKey, s.postalKey),
+				})
+			}
+
+			if old.isoID != s.isoID {
+				changes = append(changes, change{
+					Description: fmt.Sprintf("%s ISO ID changed [%s]: %s from %q to %q", s.kind, language, s.path, old.isoID, s.isoID),
+				})
+			}
+
+			if old.latinizedName != s.latinizedName {
+				changes = append(changes, change{
+					Description: fmt.Sprintf("%s latinized name changed [%s]: %s from %q to %q", s.kind, language, s.path, old.latinizedName, s.latinizedName),
+				})
+			}
+		}
+
+		for _, s := range oldSubdivisions {
+			if _, ok := newByPath[s.path]; !ok {
+				changes = append(changes, change{
+					Description: fmt.Sprintf("%s removed [%s]: %s (%s)", s.kind, language, s.path, s.name),
+				})
+			}
+		}
+	}
+
+	return changes
+}
+
+func diffRedirects(oldRedirects []administrativeAreaRedirect, newRedirects []administrativeAreaRedirect) []change {
+
+	oldByID := map[string]administrativeAreaRedirect{}
+
+	for _, redirect := range oldRedirects {
+		oldByID[redirect.ID] = redirect
+	}
+
+	newByID := map[string]administrativeAreaRedirect{}
+
+	for _, redirect := range newRedirects {
+		newByID[redirect.ID] = redirect
+	}
+
+	var changes []change
+
+	for _, redirect := range newRedirects {
+
+		old, ok := oldByID[redirect.ID]
+
+		switch {
+		case !ok:
+			changes = append(changes, change{
+				Description: fmt.Sprintf("Administrative area redirect added: %s (%s) to %s", redirect.ID, redirect.Name, redirect.CountryCode),
+			})
+
+		case old != redirect:
+			changes = append(changes, change{
+				Description: fmt.Sprintf("Administrative area redirect changed: %s (%s) to %s, was %s (%s) to %s", redirect.ID, redirect.Name, redirect.CountryCode, old.ID, old.Name, old.CountryCode),
+			})
+		}
+	}
+
+	for _, redirect := range oldRedirects {
+		if _, ok := newByID[redirect.ID]; !ok {
+			changes = append(changes, change{
+				Description: fmt.Sprintf("Administrative area redirect removed: %s (%s) to %s", redirect.ID, redirect.Name, redirect.CountryCode),
+			})
+		}
+	}
+
+	return changes
+}
+
+// flattenPostCodeRegexes returns the post code regexes keyed by the path of IDs of the subdivision they belong to. The
+// regex of the country has an empty path.
+func flattenPostCodeRegexes(p postCodeRegex, path string, result map[string]string) map[string]string {
+
+	if p.Regex != "" {
+		result[path] = p.Regex
+	}
+
+	for id, sub := range p.SubdivisionRegex {
+
+		subPath := id
+
+		if path != "" {
+			subPath = path + "/" + id
+		}
+
+		flattenPostCodeRegexes(sub, subPath, result)
+	}
+
+	return result
+}
+
+func diffPostCodeRegexes(oldRegex postCodeRegex, newRegex postCodeRegex, samples int) []change {
+
+	oldRegexes := flattenPostCodeRegexes(oldRegex, "", map[string]string{})
+	newRegexes := flattenPostCodeRegexes(newRegex, "", map[string]string{})
+
+	paths := sortedKeys(oldRegexes)
+
+	for path := range newRegexes {
+		if _, ok := oldRegexes[path]; !ok {
+			paths = append(paths, path)
+		}
+	}
+
+	sort.Strings(paths)
+
+	var changes []change
+
+	for _, path := range paths {
+
+		what := "Post code regex"
+
+		if path != "" {
+			what += " for " + path
+		}
+
+		oldValue, inOld := oldRegexes[path]
+		newValue, inNew := newRegexes[path]
+
+		switch {
+		case !inOld:
+			changes = append(changes, change{
+				Description: fmt.Sprintf("%s added: %q", what, newValue),
+			})
+
+		case !inNew:
+			changes = append(changes, change{
+				Description: fmt.Sprintf("%s removed: %q", what, oldValue),
+			})
+
+		case oldValue != newValue:
+			changes = append(changes, change{
+				Description: fmt.Sprintf("%s changed from %q to %q", what, oldValue, newValue),
+				Details:     postCodeRegexSamples(oldValue, newValue, samples),
+			})
+		}
+	}
+
+	return changes
+}
+
+// postCodeRegexSamples returns details listing sample post codes that are valid using the old regex but not the new
+// regex, and the other way around.
+func postCodeRegexSamples(oldValue string, newValue string, samples int) []string {
+
+	oldRegex, err := regexp.Compile(oldValue)
+
+	if err != nil {
+		return []string{fmt.Sprintf("Invalid old regex: %s", err)}
+	}
+
+	newRegex, err := regexp.Compile(newValue)
+
+	if err != nil {
+		return []string{fmt.Sprintf("Invalid new regex: %s", err)}
+	}
+
+	var details []string
+
+	if noLongerValid := switchingSamples(oldRegex, newRegex, samples); len(noLongerValid) > 0 {
+		details = append(details, "No longer valid: "+strings.Join(noLongerValid, ", "))
+	}
+
+	if nowValid := switchingSamples(newRegex, oldRegex, samples); len(nowValid) > 0 {
+		details = append(details, "Now valid: "+strings.Join(nowValid, ", "))
+	}
+
+	return details
+}
+
+// switchingSamples returns up to limit sample post codes that match from but do not match to.
+func switchingSamples(from *regexp.Regexp, to *regexp.Regexp, limit int) []string {
+
+	var result []string
+
+	for _, sample := range regexSamples(from.String()) {
+
+		if len(result) >= limit {
+			break
+		}
+
+		if !to.MatchString(sample) {
+			result = append(result, sample)
+		}
+	}
+
+	return result
+}
+
+// regexSamples generates sample strings matching a regex. The samples cover the alternatives of the regex and the
+// bounds of its character classes and repetitions, which is where changes to post code regexes usually are.
+func regexSamples(regex string) []string {
+
+	parsed, err := syntax.Parse(regex, syntax.Perl)
+
+	if err != nil {
+		return nil
+	}
+
+	compiled, err := regexp.Compile(regex)
+
+	if err != nil {
+		return nil
+	}
+
+	seen := map[string]struct{}{}
+
+	var samples []string
+
+	for _, sample := range expandRegex(parsed) {
+
+		if _, ok := seen[sample]; ok || sample == "" || !compiled.MatchString(sample) {
+			continue
+		}
+
+		seen[sample] = struct{}{}
+		samples = append(samples, sample)
+	}
+
+	return samples
+}
+
+func expandRegex(re *syntax.Regexp) []string {
+
+	switch re.Op {
+	case syntax.OpLiteral:
+		return []string{string(re.Rune)}
+
+	case syntax.OpCharClass:
+		var result []string
+
+		for i := 0; i+1 < len(re.Rune); i += 2 {
+			for _, r := range []rune{re.Rune[i], re.Rune[i+1]} {
+				if unicode.IsPrint(r) && !slices.Contains(result, string(r)) {
+					result = append(result, string(r))
+				}
+			}
+		}
+
+		return capSamples(result)
+
+	case syntax.OpAnyChar, syntax.OpAnyCharNotNL:
+		return []string{"0", "A"}
+
+	case syntax.OpCapture, syntax.OpPlus:
+		return expandRegex(re.Sub[0])
+
+	case syntax.OpQuest, syntax.OpStar:
+		return capSamples(append([]string{""}, expandRegex(re.Sub[0])...))
+
+	case syntax.OpRepeat:
+		sub := expandRegex(re.Sub[0])
+		counts := []int{re.Min}
+
+		if re.Max == -1 {
+			counts = append(counts, re.Min+1)
+		} else if re.Max != re.Min {
+			counts = append(counts, re.Max)
+		}
+
+		var result []string
+
+		for _, count := range counts {
+			for _, s := range sub {
+				result = append(result, strings.Repeat(s, count))
+			}
+		}
+
+		return capSamples(result)
+
+	case syntax.OpConcat:
+		result := []string{""}
+
+		for _, sub := range re.Sub {
+
+			var product []string
+
+			for _, prefix := range result {
+				for _, suffix := range expandRegex(sub) {
+					product = append(product, prefix+suffix)
+				}
+			}
+
+			result = capSamples(product)
+		}
+
+		return result
+
+	case syntax.OpAlternate:
+		var result []string
+
+		for _, sub := range re.Sub {
+			result = append(result, expandRegex(sub)...)
+		}
+
+		return capSamples(result)
+	}
+
+	// Empty-width assertions, such as ^ and $
+	return []string{""}
+}
+
+func capSamples(samples []string) []string {
+
+	if len(samples) > maxExpansion {
+		return samples[:maxExpansion]
+	}
+
+	return samples
+}
+
+// writeReport writes the changes to each country in Markdown, so that it can be used in the body of a pull request.
+func writeReport(w io.Writer, report []countryChanges) error {
+
+	if len(report) == 0 {
+		_, err := fmt.Fprintln(w, "No changes.")
+		return err
+	}
+
+	var b strings.Builder
+
+	for i, countryChanges := range report {
+
+		if i > 0 {
+			b.WriteString("\n")
+		}
+
+		fmt.Fprintf(&b, "## %s (%s)\n\n", countryChanges.Code, countryChanges.Name)
+
+		for _, change := range countryChanges.Changes {
+
+			fmt.Fprintf(&b, "- %s\n", change.Description)
+
+			for _, detail := range change.Details {
+				fmt.Fprintf(&b, "  - %s\n", detail)
+			}
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+
+	return err
+}
@@ -0,0 +1,300 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/Boostport/address"
+)
+
+func TestRegexSamples(t *testing.T) {
+
+	testCases := []struct {
+		Regex    string
+		Expected []string
+	}{
+		{
+			Regex:    `^(\d{4})$`,
+			Expected: []string{"0000", "9999"},
+		},
+		{
+			Regex:    `^2[0-9]`,
+			Expected: []string{"20", "29"},
+		},
+		{
+			Regex:    `^(\d{5}(?:[ \-]\d{4})?)$`,
+			Expected: []string{"00000", "00000 0000", "00000 9999", "00000-0000", "00000-9999", "99999", "99999 0000", "99999 9999", "99999-0000", "99999-9999"},
+		},
+		{
+			Regex:    `^(?:1|2[01])$`,
+			Expected: []string{"1", "20", "21"},
+		},
+		{
+			Regex:    `^(AD\d{3})$`,
+			Expected: []string{"AD000", "AD999"},
+		},
+		{
+			Regex:    `^([`,
+			Expected: nil,
+		},
+	}
+
+	for i, testCase := range testCases {
+		if samples := regexSamples(testCase.Regex); !reflect.DeepEqual(samples, testCase.Expected) {
+			t.Errorf("Expected samples %q for test case %d, got %q", testCase.Expected, i, samples)
+		}
+	}
+}
+
+func TestPostCodeRegexSamples(t *testing.T) {
+
+	testCases := []struct {
+		Old      string
+		New      string
+		Samples  int
+		Expected []string
+	}{
+		{
+			Old:      `^(\d{4})$`,
+			New:      `^(\d{5})$`,
+			Samples:  5,
+			Expected: []string{"No longer valid: 0000, 9999", "Now valid: 00000, 99999"},
+		},
+		{
+			Old:      `^(\d{5}(?:[ \-]\d{4})?)$`,
+			New:      `^(\d{5})$`,
+			Samples:  2,
+			Expected: []string{"No longer valid: 00000 0000, 00000 9999"},
+		},
+		{
+			Old:      `^2[0-9]`,
+			New:      `^2[0-4]`,
+			Samples:  5,
+			Expected: []string{"No longer valid: 29"},
+		},
+		{
+			Old:      `^2[0-9]`,
+			New:      `^([`,
+			Samples:  5,
+			Expected: []string{"Invalid new regex: error parsing regexp: missing closing ]: `[`"},
+		},
+	}
+
+	for i, testCase := range testCases {
+		if details := postCodeRegexSamples(testCase.Old, testCase.New, testCase.Samples); !reflect.DeepEqual(details, testCase.Expected) {
+			t.Errorf("Expected details %q for test case %d, got %q", testCase.Expected, i, details)
+		}
+	}
+}
+
+// loadFixtures processes the fixtures twice, so that one copy can be modified and compared with the other.
+func loadFixtures(t *testing.T) (map[string]country, map[string]country) {
+
+	progress = io.Discard
+
+	oldCountries, err := loadDataset(fixturesDir, defaultConcurrency)
+
+	if err != nil {
+		t.Fatalf("Unexpected error loading fixtures: %s", err)
+	}
+
+	newCountries, err := loadDataset(fixturesDir, defaultConcurrency)
+
+	if err != nil {
+		t.Fatalf("Unexpected error loading fixtures: %s", err)
+	}
+
+	return oldCountries, newCountries
+}
+
+func TestDiffData(t *testing.T) {
+
+	oldCountries, newCountries := loadFixtures(t)
+
+	if report := diffData(oldCountries, newCountries, defaultSamples); len(report) != 0 {
+		t.Fatalf("Expected no changes between identical datasets, got %v", report)
+	}
+
+	au := newCountries["AU"]
+	au.Format = strings.Replace(au.Format, "%S", "%S%n", 1)
+	au.RequiredFields = 1<<address.StreetAddress | 1<<address.Locality | 1<<address.AdministrativeArea
+	au.PostCodeRegex.Regex = `^(\d{5})$`
+	au.PostCodeRegex.SubdivisionRegex["NT"] = postCodeRegex{Regex: `^08`}
+	delete(au.PostCodeRegex.SubdivisionRegex, "WA")
+
+	adminAreas := slices.Clone(au.AdministrativeAreas["en"])
+	adminAreas[0].Name = "Capital Territory"
+
+	adminAreas = slices.DeleteFunc(adminAreas, func(a administrativeArea) bool {
+		return a.ID == "TAS"
+	})
+
+	adminAreas = append(adminAreas, administrativeArea{ID: "XX", Name: "New Territory", PostalKey: "XX"})
+	au.AdministrativeAreas = map[string][]administrativeArea{"en": adminAreas}
+
+	newCountries["AU"] = au
+
+	delete(newCountries, "PR")
+	newCountries["NZ"] = country{ID: "NZ", Name: "NEW ZEALAND"}
+
+	report := diffData(oldCountries, newCountries, defaultSamples)
+
+	expected := []countryChanges{
+		{
+			Code: "AU",
+			Name: "AUSTRALIA",
+			Changes: []change{
+				{Description: `Format changed from "%O%n%N%n%A%n%C %S %Z" to "%O%n%N%n%A%n%C %S%n %Z"`},
+				{Description: "Required fields removed: PostCode"},
+				{Description: `Administrative area renamed [en]: ACT from "Australian Capital Territory" to "Capital Territory"`},
+				{Description: "Administrative area added [en]: XX (New Territory)"},
+				{Description: "Administrative area removed [en]: TAS (Tasmania)"},
+				{
+					Description: `Post code regex changed from "^(\\d{4})$" to "^(\\d{5})$"`,
+					Details:     []string{"No longer valid: 0000, 9999", "Now valid: 00000, 99999"},
+				},
+				{
+					Description: `Post code regex for NT changed from "^0[89]" to "^08"`,
+					Details:     []string{"No longer valid: 09"},
+				},
+				{Description: `Post code regex for WA removed: "^6|0872"`},
+			},
+		},
+		{
+			Code:    "NZ",
+			Name:    "NEW ZEALAND",
+			Changes: []change{{Description: "Country added"}},
+		},
+		{
+			Code:    "PR",
+			Name:    "PUERTO RICO",
+			Changes: []change{{Description: "Country removed"}},
+		},
+	}
+
+	if !reflect.DeepEqual(report, expected) {
+		t.Errorf("Unexpected report, expected:\n%+v\ngot:\n%+v", expected, report)
+	}
+}
+
+func TestDiffDataSubdivisions(t *testing.T) {
+
+	oldCountries, newCountries := loadFixtures(t)
+
+	ca := newCountries["CA"]
+	adminAreas := slices.Clone(ca.AdministrativeAreas["fr"])
+	adminAreas[0].PostalKey = "ALB"
+	adminAreas[0].ISOID = "ALB"
+	adminAreas[0].LatinizedName = "Alberta"
+	adminAreas[1].Localities = []locality{
+		{ID: "Victoria", Name: "Victoria", DependentLocalities: []dependentLocality{{ID: "James Bay", Name: "James Bay"}}},
+	}
+	ca.AdministrativeAreas = map[string][]administrativeArea{
+		"en": ca.AdministrativeAreas["en"],
+		"fr": adminAreas,
+	}
+	ca.PostURL = "https://www.canadapost-postescanada.ca/"
+	ca.AdministrativeAreaRedirects = []administrativeAreaRedirect{{ID: "PM", Name: "Saint Pierre and Miquelon", PostalKey: "PM", CountryCode: "PM"}}
+	newCountries["CA"] = ca
+
+	report := diffData(oldCountries, newCountries, defaultSamples)
+
+	expected := []countryChanges{
+		{
+			Code: "CA",
+			Name: "CANADA",
+			Changes: []change{
+				{Description: `Post office URL changed from "https://www.canadapost.ca/cpo/mc/personal/postalcode/fpc.jsf" to "https://www.canadapost-postescanada.ca/"`},
+				{Description: `Administrative area postal key changed [fr]: AB from "AB" to "ALB"`},
+				{Description: `Administrative area ISO ID changed [fr]: AB from "AB" to "ALB"`},
+				{Description: `Administrative area latinized name changed [fr]: AB from "" to "Alberta"`},
+				{Description: "Locality added [fr]: BC/Victoria (Victoria)"},
+				{Description: "Dependent locality added [fr]: BC/Victoria/James Bay (James Bay)"},
+				{Description: "Administrative area redirect added: PM (Saint Pierre and Miquelon) to PM"},
+			},
+		},
+	}
+
+	if !reflect.DeepEqual(report, expected) {
+		t.Errorf("Unexpected report, expected:\n%+v\ngot:\n%+v", expected, report)
+	}
+}
+
+func TestDiffDataAgainstGenerated(t *testing.T) {
+
+	progress = io.Discard
+
+	fixtures, err := loadDataset(fixturesDir, defaultConcurrency)
+
+	if err != nil {
+		t.Fatalf("Unexpected error loading fixtures: %s", err)
+	}
+
+	generated, err := loadDataset("../data.generated.bin", defaultConcurrency)
+
+	if err != nil {
+		t.Fatalf("Unexpected error loading the generated data: %s", err)
+	}
+
+	for code := range generated {
+		if _, ok := fixtures[code]; !ok {
+			delete(generated, code)
+		}
+	}
+
+	if report := diffData(generated, fixtures, defaultSamples); len(report) != 0 {
+		t.Errorf("Expected no changes between the fixtures and the generated data, got %+v", report)
+	}
+}
+
+func TestWriteReport(t *testing.T) {
+
+	testCases := []struct {
+		Report   []countryChanges
+		Expected string
+	}{
+		{
+			Expected: "No changes.\n",
+		},
+		{
+			Report: []countryChanges{
+				{
+					Code: "AU",
+					Name: "AUSTRALIA",
+					Changes: []change{
+						{Description: "Required fields removed: PostCode"},
+						{Description: "Post code regex changed", Details: []string{"No longer valid: 0000"}},
+					},
+				},
+				{
+					Code:    "NZ",
+					Name:    "NEW ZEALAND",
+					Changes: []change{{Description: "Country added"}},
+				},
+			},
+			Expected: "## AU (AUSTRALIA)\n\n" +
+				"- Required fields removed: PostCode\n" +
+				"- Post code regex changed\n" +
+				"  - No longer valid: 0000\n" +
+				"\n## NZ (NEW ZEALAND)\n\n" +
+				"- Country added\n",
+		},
+	}
+
+	for i, testCase := range testCases {
+
+		var b bytes.Buffer
+
+		if err := writeReport(&b, testCase.Report); err != nil {
+			t.Errorf("Unexpected error writing report for test case %d: %s", i, err)
+		}
+
+		if b.String() != testCase.Expected {
+			t.Errorf("Expected report %q for test case %d, got %q", testCase.Expected, i, b.String())
+		}
+	}
+}
@@ -0,0 +1,206 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+)
+
+// The data can be fetched from the service or from a snapshot directory. The snapshot stores each document fetched
+// from the service as <id>.json, for example data/AU.json and data/CA--fr.json, which is the layout read by
+// address.LoadRegistry.
+
+const (
+	modeLive   = "live"
+	modeRecord = "record"
+	modeReplay = "replay"
+	modeCache  = "cache"
+)
+
+// fetcher gets a document by its ID, such as "data", "data/AU" or "data/CA--fr".
+type fetcher interface {
+	fetch(id string) ([]byte, error)
+}
+
+// newFetcher returns the fetcher for a mode, which uses live to fetch documents from the service. All modes except live
+// require a snapshot directory.
+func newFetcher(mode string, snapshot string, live *httpFetcher) (fetcher, error) {
+
+	if mode != modeLive && snapshot == "" {
+		return nil, fmt.Errorf("the %s mode requires a snapshot directory", mode)
+	}
+
+	switch mode {
+	case modeLive:
+		return live, nil
+
+	case modeRecord:
+		return &recordingFetcher{
+			fetcher: live,
+			dir:     snapshot,
+		}, nil
+
+	case modeReplay:
+		return &snapshotFetcher{
+			dir: snapshot,
+		}, nil
+
+	case modeCache:
+		return &cachingFetcher{
+			snapshot: &snapshotFetcher{dir: snapshot},
+			recorder: &recordingFetcher{fetcher: live, dir: snapshot},
+		}, nil
+	}
+
+	return nil, fmt.Errorf("invalid mode %q", mode)
+}
+
+// httpFetcher fetches documents from the service. Requests that fail because of a network error, a timeout or a
+// server error are retried up to retries times, waiting backoff before the first retry and doubling the wait after
+// each retry. The URLs that still fail are kept, so that they can be reported at the end of the run. It is safe for
+// concurrent use.
+type httpFetcher struct {
+	url     string
+	client  *http.Client
+	retries int
+	backoff time.Duration
+
+	mu     sync.Mutex
+	failed []string
+}
+
+func (h *httpFetcher) fetch(id string) ([]byte, error) {
+
+	url := h.url + "/" + id
+	wait := h.backoff
+
+	for attempt := 0; ; attempt++ {
+
+		data, retry, err := h.get(url)
+
+		if err == nil {
+			return data, nil
+		}
+
+		if !retry || attempt >= h.retries {
+
+			h.mu.Lock()
+			h.failed = append(h.failed, url)
+			h.mu.Unlock()
+
+			if attempt > 0 {
+				return nil, fmt.Errorf("%s (after %d retries)", err, attempt)
+			}
+
+			return nil, err
+		}
+
+		time.Sleep(wait)
+		wait *= 2
+	}
+}
+
+// get makes a single request and reports whether the request should be retried if it fails.
+func (h *httpFetcher) get(url string) ([]byte, bool, error) {
+
+	resp, err := h.client.Get(url)
+
+	if err != nil {
+		return nil, true, fmt.Errorf("error getting %s: %s", url, err)
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		retry := resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= http.StatusInternalServerError
+		return nil, retry, fmt.Errorf("error getting %s: unexpected status %s", url, resp.Status)
+	}
+
+	data, err := io.ReadAll(resp.Body)
+
+	if err != nil {
+		return nil, true, fmt.Errorf("error reading %s: %s", url, err)
+	}
+
+	return data, false, nil
+}
+
+// failedURLs returns the sorted URLs that could not be fetched.
+func (h *httpFetcher) failedURLs() []string {
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	failed := slices.Clone(h.failed)
+	slices.Sort(failed)
+
+	return slices.Compact(failed)
+}
+
+// snapshotFetcher reads documents from a snapshot directory.
+type snapshotFetcher struct {
+	dir string
+}
+
+func (s *snapshotFetcher) fetch(id string) ([]byte, error) {
+	return os.ReadFile(snapshotPath(s.dir, id))
+}
+
+// recordingFetcher writes the documents it fetches to a snapshot directory.
+type recordingFetcher struct {
+	fetcher fetcher
+	dir     string
+}
+
+func (r *recordingFetcher) fetch(id string) ([]byte, error) {
+
+	data, err := r.fetcher.fetch(id)
+
+	if err != nil {
+		return nil, err
+	}
+
+	path := snapshotPath(r.dir, id)
+
+	err = os.MkdirAll(filepath.Dir(path), 0755)
+
+	if err != nil {
+		return nil, fmt.Errorf("error creating snapshot directory for %s: %s", id, err)
+	}
+
+	err = os.WriteFile(path, data, 0644)
+
+	if err != nil {
+		return nil, fmt.Errorf("error writing %s to the snapshot: %s", id, err)
+	}
+
+	return data, nil
+}
+
+// cachingFetcher reads documents from a snapshot, fetching and recording the documents that are missing.
+type cachingFetcher struct {
+	snapshot *snapshotFetcher
+	recorder *recordingFetcher
+}
+
+func (c *cachingFetcher) fetch(id string) ([]byte, error) {
+
+	data, err := c.snapshot.fetch(id)
+
+	if errors.Is(err, fs.ErrNotExist) {
+		return c.recorder.fetch(id)
+	}
+
+	return data, err
+}
+
+func snapshotPath(dir string, id string) string {
+	return filepath.Join(dir, filepath.FromSlash(id)+".json")
+}
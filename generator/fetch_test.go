@@ -0,0 +1,148 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestHTTPFetcherStatus(t *testing.T) {
+
+	server := httptest.NewServer(http.NotFoundHandler())
+	defer server.Close()
+
+	f := &httpFetcher{url: server.URL, client: server.Client()}
+
+	if _, err := f.fetch("data/XX"); err == nil {
+		t.Error("Expected error for a missing document, got nil")
+	}
+}
+
+func TestNewFetcher(t *testing.T) {
+
+	testCases := []struct {
+		Mode     string
+		Snapshot string
+		Error    bool
+	}{
+		{Mode: modeLive},
+		{Mode: modeRecord, Snapshot: "snapshot"},
+		{Mode: modeReplay, Snapshot: "snapshot"},
+		{Mode: modeCache, Snapshot: "snapshot"},
+		{Mode: modeReplay, Error: true},
+		{Mode: "offline", Snapshot: "snapshot", Error: true},
+	}
+
+	for i, testCase := range testCases {
+
+		_, err := newFetcher(testCase.Mode, testCase.Snapshot, &httpFetcher{})
+
+		if (err != nil) != testCase.Error {
+			t.Errorf("Unexpected error result for test case %d: %v", i, err)
+		}
+	}
+}
+
+func TestHTTPFetcherRetry(t *testing.T) {
+
+	testCases := []struct {
+		Failures         int
+		Status           int
+		Retries          int
+		ExpectedRequests int64
+		Error            bool
+	}{
+		{Failures: 0, Status: http.StatusServiceUnavailable, Retries: 3, ExpectedRequests: 1},
+		{Failures: 2, Status: http.StatusServiceUnavailable, Retries: 3, ExpectedRequests: 3},
+		{Failures: 2, Status: http.StatusTooManyRequests, Retries: 2, ExpectedRequests: 3},
+		{Failures: 5, Status: http.StatusInternalServerError, Retries: 2, ExpectedRequests: 3, Error: true},
+		{Failures: 5, Status: http.StatusNotFound, Retries: 3, ExpectedRequests: 1, Error: true},
+		{Failures: 1, Status: http.StatusBadGateway, Retries: 0, ExpectedRequests: 1, Error: true},
+	}
+
+	for i, testCase := range testCases {
+
+		var requests atomic.Int64
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+
+			if requests.Add(1) <= int64(testCase.Failures) {
+				w.WriteHeader(testCase.Status)
+				return
+			}
+
+			w.Write([]byte(`{"id": "data/AU"}`))
+		}))
+
+		f := &httpFetcher{
+			url:     server.URL,
+			client:  server.Client(),
+			retries: testCase.Retries,
+			backoff: time.Millisecond,
+		}
+
+		data, err := f.fetch("data/AU")
+
+		server.Close()
+
+		if (err != nil) != testCase.Error {
+			t.Errorf("Unexpected error result for test case %d: %v", i, err)
+		}
+
+		if !testCase.Error && string(data) != `{"id": "data/AU"}` {
+			t.Errorf("Unexpected data for test case %d: %s", i, data)
+		}
+
+		if requests.Load() != testCase.ExpectedRequests {
+			t.Errorf("Expected %d requests for test case %d, got %d", testCase.ExpectedRequests, i, requests.Load())
+		}
+
+		var expectedFailedURLs []string
+
+		if testCase.Error {
+			expectedFailedURLs = []string{server.URL + "/data/AU"}
+		}
+
+		if failedURLs := f.failedURLs(); !reflect.DeepEqual(failedURLs, expectedFailedURLs) {
+			t.Errorf("Expected failed URLs %v for test case %d, got %v", expectedFailedURLs, i, failedURLs)
+		}
+	}
+}
+
+func TestHTTPFetcherTimeout(t *testing.T) {
+
+	var requests atomic.Int64
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+
+		requests.Add(1)
+
+		select {
+		case <-time.After(time.Second):
+		case <-r.Context().Done():
+		}
+	}))
+
+	defer server.Close()
+
+	client := server.Client()
+	client.Timeout = 10 * time.Millisecond
+
+	f := &httpFetcher{
+		url:     server.URL,
+		client:  client,
+		retries: 1,
+		backoff: time.Millisecond,
+	}
+
+	if _, err := f.fetch("data/AU"); err == nil {
+		t.Error("Expected error for a request that times out, got nil")
+	}
+
+	if requests.Load() != 2 {
+		t.Errorf("Expected the request that timed out to be retried once, got %d requests", requests.Load())
+	}
+}
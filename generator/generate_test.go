@@ -0,0 +1,313 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Boostport/address/internal/addressdata"
+)
+
+const fixturesDir = "../testdata/addressdata"
+
+// checkGenerated checks that the countries generated using a fetcher match the countries processed directly from the
+// fixtures, which are copies of the service's data.
+func checkGenerated(t *testing.T, countries map[string]country) {
+
+	if keys := sortedKeys(countries); !reflect.DeepEqual(keys, []string{"AU", "CA", "PR", "ZZ"}) {
+		t.Fatalf("Expected AU, CA, PR and ZZ to be generated, got %v", keys)
+	}
+
+	readFixture := func(id string) ([]byte, error) {
+		return os.ReadFile(snapshotPath(fixturesDir, id))
+	}
+
+	for code, c := range countries {
+
+		expected, err := addressdata.ProcessCountry(readFixture, code)
+
+		if err != nil {
+			t.Fatalf("Unexpected error processing the fixtures of %s: %s", code, err)
+		}
+
+		if !reflect.DeepEqual(c, expected) {
+			t.Errorf("Generated data for %s does not match the fixtures", code)
+		}
+	}
+}
+
+func TestGenerateReplay(t *testing.T) {
+
+	f, err := newFetcher(modeReplay, fixturesDir, &httpFetcher{})
+
+	if err != nil {
+		t.Fatalf("Unexpected error creating fetcher: %s", err)
+	}
+
+	countries, excluded, err := generate(f, "", defaultConcurrency)
+
+	if err != nil {
+		t.Fatalf("Unexpected error generating data: %s", err)
+	}
+
+	if len(excluded) != 0 {
+		t.Errorf("Expected no excluded countries, got %v", excluded)
+	}
+
+	checkGenerated(t, countries)
+}
+
+func TestGenerateReplayAllowlist(t *testing.T) {
+
+	f := &snapshotFetcher{dir: fixturesDir}
+
+	countries, excluded, err := generate(f, "AU,PR", defaultConcurrency)
+
+	if err != nil {
+		t.Fatalf("Unexpected error generating data: %s", err)
+	}
+
+	if keys := sortedKeys(countries); !reflect.DeepEqual(keys, []string{"AU", "PR", "ZZ"}) {
+		t.Errorf("Expected AU, PR and ZZ to be generated, got %v", keys)
+	}
+
+	if !reflect.DeepEqual(excluded, []string{"CA"}) {
+		t.Errorf("Expected CA to be excluded, got %v", excluded)
+	}
+}
+
+func TestGenerateReplayMissingSnapshot(t *testing.T) {
+
+	f := &snapshotFetcher{dir: t.TempDir()}
+
+	if _, _, err := generate(f, "", defaultConcurrency); err == nil {
+		t.Error("Expected error generating data from an empty snapshot, got nil")
+	}
+}
+
+// newFixturesServer returns a server serving the fixtures in the same way as the service.
+func newFixturesServer(t *testing.T, requests *atomic.Int64) *httptest.Server {
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+
+		requests.Add(1)
+
+		http.ServeFile(w, r, snapshotPath(fixturesDir, strings.TrimPrefix(r.URL.Path, "/")))
+	}))
+
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func TestGenerateRecord(t *testing.T) {
+
+	var requests atomic.Int64
+	server := newFixturesServer(t, &requests)
+	snapshot := t.TempDir()
+
+	f := &recordingFetcher{
+		fetcher: &httpFetcher{url: server.URL, client: server.Client()},
+		dir:     snapshot,
+	}
+
+	countries, _, err := generate(f, "", defaultConcurrency)
+
+	if err != nil {
+		t.Fatalf("Unexpected error generating data: %s", err)
+	}
+
+	checkGenerated(t, countries)
+
+	for _, id := range []string{"data", "data/AU", "data/CA", "data/CA--fr", "data/PR", "data/ZZ"} {
+
+		recorded, err := os.ReadFile(snapshotPath(snapshot, id))
+
+		if err != nil {
+			t.Errorf("Expected %s to be recorded: %s", id, err)
+			continue
+		}
+
+		fixture, _ := os.ReadFile(snapshotPath(fixturesDir, id))
+
+		if !bytes.Equal(recorded, fixture) {
+			t.Errorf("Recorded %s does not match the data served", id)
+		}
+	}
+
+	replayed, _, err := generate(&snapshotFetcher{dir: snapshot}, "", defaultConcurrency)
+
+	if err != nil {
+		t.Fatalf("Unexpected error replaying the recorded snapshot: %s", err)
+	}
+
+	if !reflect.DeepEqual(replayed, countries) {
+		t.Error("Data generated from the recorded snapshot does not match the recorded data")
+	}
+}
+
+func TestGenerateCache(t *testing.T) {
+
+	var requests atomic.Int64
+	server := newFixturesServer(t, &requests)
+	snapshot := t.TempDir()
+
+	f := &cachingFetcher{
+		snapshot: &snapshotFetcher{dir: snapshot},
+		recorder: &recordingFetcher{
+			fetcher: &httpFetcher{url: server.URL, client: server.Client()},
+			dir:     snapshot,
+		},
+	}
+
+	if _, _, err := generate(f, "", defaultConcurrency); err != nil {
+		t.Fatalf("Unexpected error generating data: %s", err)
+	}
+
+	fetched := requests.Load()
+
+	if fetched == 0 {
+		t.Fatal("Expected the data to be fetched from the server")
+	}
+
+	countries, _, err := generate(f, "", defaultConcurrency)
+
+	if err != nil {
+		t.Fatalf("Unexpected error generating data from the cache: %s", err)
+	}
+
+	if requests.Load() != fetched {
+		t.Errorf("Expected the cached data to be used, got %d more requests", requests.Load()-fetched)
+	}
+
+	checkGenerated(t, countries)
+}
+
+func TestGeneratePartialFailure(t *testing.T) {
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+
+		if r.URL.Path == "/data/CA" {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		http.ServeFile(w, r, snapshotPath(fixturesDir, strings.TrimPrefix(r.URL.Path, "/")))
+	}))
+
+	defer server.Close()
+
+	f := &httpFetcher{
+		url:     server.URL,
+		client:  server.Client(),
+		retries: 1,
+		backoff: time.Millisecond,
+	}
+
+	countries, _, err := generate(f, "", defaultConcurrency)
+
+	var failed *failedCountriesError
+
+	if !errors.As(err, &failed) {
+		t.Fatalf("Expected *failedCountriesError, got %v", err)
+	}
+
+	if keys := sortedKeys(failed.errors); !reflect.DeepEqual(keys, []string{"CA"}) {
+		t.Errorf("Expected CA to fail, got %v", keys)
+	}
+
+	if keys := sortedKeys(countries); !reflect.DeepEqual(keys, []string{"AU", "PR", "ZZ"}) {
+		t.Errorf("Expected the other countries to be processed, got %v", keys)
+	}
+
+	if failedURLs := f.failedURLs(); !reflect.DeepEqual(failedURLs, []string{server.URL + "/data/CA"}) {
+		t.Errorf("Expected the URL of CA to be reported, got %v", failedURLs)
+	}
+}
+
+func TestGenerateConcurrency(t *testing.T) {
+
+	for _, concurrency := range []int{1, 2} {
+
+		var inFlight, maxInFlight atomic.Int64
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+
+			current := inFlight.Add(1)
+			defer inFlight.Add(-1)
+
+			for {
+				highest := maxInFlight.Load()
+
+				if current <= highest || maxInFlight.CompareAndSwap(highest, current) {
+					break
+				}
+			}
+
+			time.Sleep(5 * time.Millisecond)
+
+			http.ServeFile(w, r, snapshotPath(fixturesDir, strings.TrimPrefix(r.URL.Path, "/")))
+		}))
+
+		f := &httpFetcher{url: server.URL, client: server.Client()}
+
+		_, _, err := generate(f, "", concurrency)
+
+		server.Close()
+
+		if err != nil {
+			t.Fatalf("Unexpected error generating data with a concurrency of %d: %s", concurrency, err)
+		}
+
+		if maxInFlight.Load() > int64(concurrency) {
+			t.Errorf("Expected at most %d requests at the same time, got %d", concurrency, maxInFlight.Load())
+		}
+	}
+}
+
+func TestWriteData(t *testing.T) {
+
+	countries, _, err := generate(&snapshotFetcher{dir: fixturesDir}, "", defaultConcurrency)
+
+	if err != nil {
+		t.Fatalf("Unexpected error generating data: %s", err)
+	}
+
+	dir := t.TempDir()
+
+	err = writeData(dir, countries, []string{"DE", "FR"})
+
+	if err != nil {
+		t.Fatalf("Unexpected error writing data: %s", err)
+	}
+
+	encoded, err := os.ReadFile(filepath.Join(dir, "data.generated.bin"))
+
+	if err != nil {
+		t.Fatalf("Unexpected error reading data.generated.bin: %s", err)
+	}
+
+	if !bytes.Equal(encoded, addressdata.Encode(countries)) {
+		t.Error("data.generated.bin does not contain the encoded data")
+	}
+
+	source, err := os.ReadFile(filepath.Join(dir, "data.generated.go"))
+
+	if err != nil {
+		t.Fatalf("Unexpected error reading data.generated.go: %s", err)
+	}
+
+	for _, expected := range []string{"//go:embed data.generated.bin", `"DE": {},`, `"FR": {},`} {
+		if !strings.Contains(string(source), expected) {
+			t.Errorf("Expected data.generated.go to contain %q", expected)
+		}
+	}
+}
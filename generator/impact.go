@@ -0,0 +1,199 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"iter"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/Boostport/address"
+)
+
+// compiledDataset is the name of the dataset compiled into the address package.
+const compiledDataset = "compiled"
+
+// runImpact runs the impact subcommand, which reads addresses and reports the addresses whose validation result or
+// formatting changes between two datasets.
+func runImpact(args []string) error {
+
+	flags := flag.NewFlagSet("impact", flag.ExitOnError)
+
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: generator impact [flags] <old> <new>\n\n")
+		fmt.Fprintf(flags.Output(), "Reads addresses as JSON objects with the fields of address.Address and reports the addresses\n")
+		fmt.Fprintf(flags.Output(), "whose validation result or formatting changes. Each dataset is either %q for the data compiled into\n", compiledDataset)
+		fmt.Fprintf(flags.Output(), "the address package, a snapshot directory or a tar archive of a snapshot.\n\n")
+		flags.PrintDefaults()
+	}
+
+	inputFlag := flags.String("input", "-", "file containing the addresses, or - for stdin")
+	languageFlag := flags.String("language", "", "language used to format the addresses (default: the language of each country)")
+	flags.Parse(args)
+
+	if flags.NArg() != 2 {
+		flags.Usage()
+		return fmt.Errorf("expected 2 datasets, got %d", flags.NArg())
+	}
+
+	before, err := loadRegistry(flags.Arg(0))
+
+	if err != nil {
+		return fmt.Errorf("error loading %s: %s", flags.Arg(0), err)
+	}
+
+	after, err := loadRegistry(flags.Arg(1))
+
+	if err != nil {
+		return fmt.Errorf("error loading %s: %s", flags.Arg(1), err)
+	}
+
+	input := os.Stdin
+
+	if *inputFlag != "-" {
+
+		input, err = os.Open(*inputFlag)
+
+		if err != nil {
+			return fmt.Errorf("error opening addresses: %s", err)
+		}
+
+		defer input.Close()
+	}
+
+	addresses, decodeErr := decodeAddresses(input)
+
+	report := address.AnalyzeImpact(before, after, addresses, *languageFlag)
+
+	if err := decodeErr(); err != nil {
+		return fmt.Errorf("error reading address %d: %s", report.Checked+1, err)
+	}
+
+	return writeImpactReport(os.Stdout, report)
+}
+
+// loadRegistry returns the registry of the compiled in data or loads a registry from a snapshot directory or tar
+// archive.
+func loadRegistry(dataset string) (*address.Registry, error) {
+
+	if dataset == compiledDataset {
+		return address.NewRegistry(), nil
+	}
+
+	info, err := os.Stat(dataset)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if info.IsDir() {
+		return address.LoadRegistry(os.DirFS(dataset))
+	}
+
+	f, err := os.Open(dataset)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer f.Close()
+
+	return address.LoadRegistryFromTar(f)
+}
+
+// decodeAddresses returns a sequence of the addresses decoded from a stream of JSON objects, such as JSON lines. The
+// sequence stops at the first error, which is returned by the error function once the sequence is done.
+func decodeAddresses(r io.Reader) (iter.Seq[address.Address], func() error) {
+
+	var err error
+
+	seq := func(yield func(address.Address) bool) {
+
+		decoder := json.NewDecoder(r)
+
+		for {
+
+			var a address.Address
+
+			if decodeErr := decoder.Decode(&a); decodeErr != nil {
+
+				if !errors.Is(decodeErr, io.EOF) {
+					err = decodeErr
+				}
+
+				return
+			}
+
+			if !yield(a) {
+				return
+			}
+		}
+	}
+
+	return seq, func() error {
+		return err
+	}
+}
+
+// writeImpactReport writes the affected addresses in Markdown, grouped by country and reason.
+func writeImpactReport(w io.Writer, report address.ImpactReport) error {
+
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "Checked %d addresses, found %d changes.\n", report.Checked, report.Len())
+
+	for _, countryCode := range sortedKeys(report.Countries) {
+
+		reasons := report.Countries[countryCode]
+
+		var sortedReasons []address.ImpactReason
+
+		for reason := range reasons {
+			sortedReasons = append(sortedReasons, reason)
+		}
+
+		sort.Slice(sortedReasons, func(i, j int) bool {
+
+			if sortedReasons[i].Kind != sortedReasons[j].Kind {
+				return sortedReasons[i].Kind < sortedReasons[j].Kind
+			}
+
+			return sortedReasons[i].Cause < sortedReasons[j].Cause
+		})
+
+		fmt.Fprintf(&b, "\n## %s\n", countryCode)
+
+		for _, reason := range sortedReasons {
+
+			impacts := reasons[reason]
+
+			fmt.Fprintf(&b, "\n### %s (%d)\n\n", reason, len(impacts))
+
+			for _, impact := range impacts {
+
+				encoded, err := json.Marshal(impact.Address)
+
+				if err != nil {
+					return err
+				}
+
+				fmt.Fprintf(&b, "- `%s`\n", encoded)
+
+				if reason.Kind == address.ImpactFormatChanged {
+					fmt.Fprintf(&b, "  - Before: %s\n", strings.ReplaceAll(impact.Before, "\n", " / "))
+					fmt.Fprintf(&b, "  - After: %s\n", strings.ReplaceAll(impact.After, "\n", " / "))
+				} else if reason.Kind == address.ImpactValidationChanged {
+					fmt.Fprintf(&b, "  - Before: %s\n", impact.Before)
+				}
+			}
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+
+	return err
+}
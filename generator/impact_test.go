@@ -0,0 +1,104 @@
+package main
+
+import (
+	"bytes"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/Boostport/address"
+)
+
+func TestDecodeAddresses(t *testing.T) {
+
+	testCases := []struct {
+		Input    string
+		Expected []address.Address
+		Error    bool
+	}{
+		{
+			Input: `{"Country":"AU","PostCode":"3000"}
+{"Country":"NZ","StreetAddress":["1 Queen Street"]}`,
+			Expected: []address.Address{
+				{Country: "AU", PostCode: "3000"},
+				{Country: "NZ", StreetAddress: []string{"1 Queen Street"}},
+			},
+		},
+		{
+			Input: "",
+		},
+		{
+			Input:    `{"Country":"AU"} {"Country":`,
+			Expected: []address.Address{{Country: "AU"}},
+			Error:    true,
+		},
+	}
+
+	for i, testCase := range testCases {
+
+		addresses, decodeErr := decodeAddresses(strings.NewReader(testCase.Input))
+
+		decoded := slices.Collect(addresses)
+
+		if err := decodeErr(); (err != nil) != testCase.Error {
+			t.Errorf("Unexpected error result for test case %d: %v", i, err)
+		}
+
+		if !slices.EqualFunc(decoded, testCase.Expected, func(a, b address.Address) bool {
+			return a.Country == b.Country && a.PostCode == b.PostCode && slices.Equal(a.StreetAddress, b.StreetAddress)
+		}) {
+			t.Errorf("Expected addresses %+v for test case %d, got %+v", testCase.Expected, i, decoded)
+		}
+	}
+}
+
+func TestImpactAgainstSnapshot(t *testing.T) {
+
+	compiled, err := loadRegistry(compiledDataset)
+
+	if err != nil {
+		t.Fatalf("Unexpected error loading the compiled data: %s", err)
+	}
+
+	snapshot, err := loadRegistry(fixturesDir)
+
+	if err != nil {
+		t.Fatalf("Unexpected error loading the snapshot: %s", err)
+	}
+
+	input := `{"Country":"AU","StreetAddress":["525 Collins Street"],"Locality":"Melbourne","AdministrativeArea":"VIC","PostCode":"3000"}
+{"Country":"US","StreetAddress":["1600 Amphitheatre Parkway"],"Locality":"Mountain View","AdministrativeArea":"CA","PostCode":"94043"}`
+
+	addresses, decodeErr := decodeAddresses(strings.NewReader(input))
+
+	report := address.AnalyzeImpact(compiled, snapshot, addresses, "")
+
+	if err := decodeErr(); err != nil {
+		t.Fatalf("Unexpected error decoding addresses: %s", err)
+	}
+
+	var b bytes.Buffer
+
+	if err := writeImpactReport(&b, report); err != nil {
+		t.Fatalf("Unexpected error writing report: %s", err)
+	}
+
+	expected := "Checked 2 addresses, found 2 changes.\n" +
+		"\n## US\n" +
+		"\n### now invalid: invalid country code (1)\n\n" +
+		"- `{\"Country\":\"US\",\"Name\":\"\",\"Organization\":\"\",\"StreetAddress\":[\"1600 Amphitheatre Parkway\"],\"DependentLocality\":\"\",\"Locality\":\"Mountain View\",\"AdministrativeArea\":\"CA\",\"PostCode\":\"94043\",\"SortingCode\":\"\"}`\n" +
+		"\n### format changed (1)\n\n" +
+		"- `{\"Country\":\"US\",\"Name\":\"\",\"Organization\":\"\",\"StreetAddress\":[\"1600 Amphitheatre Parkway\"],\"DependentLocality\":\"\",\"Locality\":\"Mountain View\",\"AdministrativeArea\":\"CA\",\"PostCode\":\"94043\",\"SortingCode\":\"\"}`\n" +
+		"  - Before: 1600 Amphitheatre Parkway / Mountain View, California 94043 / United States\n" +
+		"  - After: 1600 Amphitheatre Parkway / Mountain View\n"
+
+	if b.String() != expected {
+		t.Errorf("Unexpected report, expected:\n%s\ngot:\n%s", expected, b.String())
+	}
+}
+
+func TestLoadRegistryMissing(t *testing.T) {
+	if _, err := loadRegistry(t.TempDir() + "/missing"); err == nil {
+		t.Error("Expected error loading a missing dataset, got nil")
+	}
+}
@@ -0,0 +1,171 @@
+package address
+
+import (
+	"iter"
+	"slices"
+	"strings"
+)
+
+// ImpactKind is the kind of change to an address between two registries.
+type ImpactKind int
+
+const (
+	// ImpactNowInvalid indicates that the address was valid, but is now invalid.
+	ImpactNowInvalid ImpactKind = iota
+	// ImpactNowValid indicates that the address was invalid, but is now valid.
+	ImpactNowValid
+	// ImpactValidationChanged indicates that the address is still invalid, but for different reasons.
+	ImpactValidationChanged
+	// ImpactFormatChanged indicates that the address is formatted differently by the DefaultFormatter.
+	ImpactFormatChanged
+)
+
+func (k ImpactKind) String() string {
+	switch k {
+	case ImpactNowInvalid:
+		return "now invalid"
+	case ImpactNowValid:
+		return "now valid"
+	case ImpactValidationChanged:
+		return "validation changed"
+	case ImpactFormatChanged:
+		return "format changed"
+	}
+
+	return "unknown"
+}
+
+// ImpactReason is the reason an address is affected by a change of registry. For validation changes, Cause contains
+// the validation errors that the address now fails, or that it used to fail if it is now valid. For format changes,
+// Cause is empty.
+type ImpactReason struct {
+	Kind  ImpactKind
+	Cause string
+}
+
+func (r ImpactReason) String() string {
+
+	if r.Cause == "" {
+		return r.Kind.String()
+	}
+
+	return r.Kind.String() + ": " + r.Cause
+}
+
+// Impact describes an address affected by a change of registry. For validation changes, Before and After contain the
+// validation errors, which are empty if the address is valid. For format changes, they contain the formatted address.
+type Impact struct {
+	Address Address
+	Reason  ImpactReason
+	Before  string
+	After   string
+}
+
+// ImpactReport contains the addresses affected by a change of registry, grouped by country code and reason.
+// Checked is the number of addresses that were checked.
+type ImpactReport struct {
+	Checked   int
+	Countries map[string]map[ImpactReason][]Impact
+}
+
+// Len returns the number of impacts in the report. An address that is validated and formatted differently has an
+// impact for each.
+func (r ImpactReport) Len() int {
+
+	n := 0
+
+	for _, reasons := range r.Countries {
+		for _, impacts := range reasons {
+			n += len(impacts)
+		}
+	}
+
+	return n
+}
+
+// AnalyzeImpact validates and formats each address using the before and after registries and reports the addresses
+// whose validation result or DefaultFormatter output changes. This can be used to check stored addresses before
+// switching to updated data, for example a registry created using LoadRegistry from a newer snapshot of the data.
+// The language is passed to the DefaultFormatter.
+func AnalyzeImpact(before, after *Registry, addresses iter.Seq[Address], language string) ImpactReport {
+
+	report := ImpactReport{
+		Countries: map[string]map[ImpactReason][]Impact{},
+	}
+
+	add := func(address Address, impact Impact) {
+
+		reasons, ok := report.Countries[address.Country]
+
+		if !ok {
+			reasons = map[ImpactReason][]Impact{}
+			report.Countries[address.Country] = reasons
+		}
+
+		reasons[impact.Reason] = append(reasons[impact.Reason], impact)
+	}
+
+	for address := range addresses {
+
+		report.Checked++
+
+		beforeErrs := validationErrors(before.Validate(address))
+		afterErrs := validationErrors(after.Validate(address))
+
+		if !slices.Equal(beforeErrs, afterErrs) {
+
+			impact := Impact{
+				Address: address,
+				Before:  strings.Join(beforeErrs, "; "),
+				After:   strings.Join(afterErrs, "; "),
+			}
+
+			switch {
+			case len(beforeErrs) == 0:
+				impact.Reason = ImpactReason{Kind: ImpactNowInvalid, Cause: impact.After}
+			case len(afterErrs) == 0:
+				impact.Reason = ImpactReason{Kind: ImpactNowValid, Cause: impact.Before}
+			default:
+				impact.Reason = ImpactReason{Kind: ImpactValidationChanged, Cause: impact.After}
+			}
+
+			add(address, impact)
+		}
+
+		beforeFormatted := DefaultFormatter{Output: StringOutputter{}, Registry: before}.Format(address, language)
+		afterFormatted := DefaultFormatter{Output: StringOutputter{}, Registry: after}.Format(address, language)
+
+		if beforeFormatted != afterFormatted {
+			add(address, Impact{
+				Address: address,
+				Reason:  ImpactReason{Kind: ImpactFormatChanged},
+				Before:  beforeFormatted,
+				After:   afterFormatted,
+			})
+		}
+	}
+
+	return report
+}
+
+// validationErrors returns the sorted messages of the errors joined by Validate.
+func validationErrors(err error) []string {
+
+	if err == nil {
+		return nil
+	}
+
+	var messages []string
+
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		for _, e := range joined.Unwrap() {
+			messages = append(messages, validationErrors(e)...)
+		}
+	} else {
+		messages = append(messages, err.Error())
+	}
+
+	slices.Sort(messages)
+
+	return messages
+}
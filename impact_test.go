@@ -0,0 +1,125 @@
+package address
+
+import (
+	"reflect"
+	"slices"
+	"testing"
+)
+
+func TestAnalyzeImpact(t *testing.T) {
+
+	before := NewRegistry()
+
+	after := NewRegistry(
+		WithCountryOverride("AU", CountryOverride{
+			Format:   "%N%n%O%n%A%n%C %S %Z",
+			Required: []Field{Organization, StreetAddress, Locality, AdministrativeArea, PostCode},
+			Allowed:  []Field{Name, Organization, StreetAddress, Locality, AdministrativeArea, PostCode, SortingCode},
+		}),
+	)
+
+	base := []func(*Address){
+		WithStreetAddress([]string{"525 Collins Street"}),
+		WithLocality("Melbourne"),
+		WithAdministrativeArea("VIC"),
+		WithPostCode("3000"),
+		WithCountry("AU"),
+	}
+
+	formatChanged := New(append(base, WithName("John Smith"), WithOrganization("Company Pty Ltd"))...)
+	nowInvalid := New(append(base, WithName("John Smith"))...)
+	nowValid := New(append(base, WithOrganization("Company Pty Ltd"), WithSortingCode("X"))...)
+	validationChanged := New(append(base, WithName("John Smith"), WithPostCode("30000"))...)
+
+	unchanged := New(
+		WithStreetAddress([]string{"1600 Amphitheatre Parkway"}),
+		WithLocality("Mountain View"),
+		WithAdministrativeArea("CA"),
+		WithPostCode("94043"),
+		WithCountry("US"),
+	)
+
+	addresses := []Address{formatChanged, nowInvalid, nowValid, validationChanged, unchanged}
+
+	report := AnalyzeImpact(before, after, slices.Values(addresses), "en")
+
+	if report.Checked != len(addresses) {
+		t.Errorf("Expected %d addresses to be checked, got %d", len(addresses), report.Checked)
+	}
+
+	expected := map[string]map[ImpactReason][]Impact{
+		"AU": {
+			{Kind: ImpactFormatChanged}: {
+				{
+					Address: formatChanged,
+					Reason:  ImpactReason{Kind: ImpactFormatChanged},
+					Before:  "Company Pty Ltd\nJohn Smith\n525 Collins Street\nMelbourne Victoria 3000\nAustralia",
+					After:   "John Smith\nCompany Pty Ltd\n525 Collins Street\nMelbourne Victoria 3000\nAustralia",
+				},
+			},
+			{Kind: ImpactNowInvalid, Cause: "missing required fields for AU: Organization"}: {
+				{
+					Address: nowInvalid,
+					Reason:  ImpactReason{Kind: ImpactNowInvalid, Cause: "missing required fields for AU: Organization"},
+					After:   "missing required fields for AU: Organization",
+				},
+			},
+			{Kind: ImpactNowValid, Cause: "unsupported fields for AU: SortingCode"}: {
+				{
+					Address: nowValid,
+					Reason:  ImpactReason{Kind: ImpactNowValid, Cause: "unsupported fields for AU: SortingCode"},
+					Before:  "unsupported fields for AU: SortingCode",
+				},
+			},
+			{Kind: ImpactValidationChanged, Cause: "invalid post code; missing required fields for AU: Organization"}: {
+				{
+					Address: validationChanged,
+					Reason:  ImpactReason{Kind: ImpactValidationChanged, Cause: "invalid post code; missing required fields for AU: Organization"},
+					Before:  "invalid post code",
+					After:   "invalid post code; missing required fields for AU: Organization",
+				},
+			},
+		},
+	}
+
+	if !reflect.DeepEqual(report.Countries, expected) {
+		t.Errorf("Unexpected impact report, expected:\n%+v\ngot:\n%+v", expected, report.Countries)
+	}
+
+	if report.Len() != 4 {
+		t.Errorf("Expected 4 impacts, got %d", report.Len())
+	}
+}
+
+func TestAnalyzeImpactNoChanges(t *testing.T) {
+
+	addresses := []Address{
+		New(WithCountry("AU"), WithPostCode("3000")),
+		New(WithCountry("XX")),
+	}
+
+	report := AnalyzeImpact(NewRegistry(), NewRegistry(), slices.Values(addresses), "")
+
+	if report.Checked != 2 || report.Len() != 0 {
+		t.Errorf("Expected 2 addresses to be checked without impacts, got %d checked and %d impacts", report.Checked, report.Len())
+	}
+}
+
+func TestImpactReasonString(t *testing.T) {
+
+	testCases := []struct {
+		Reason   ImpactReason
+		Expected string
+	}{
+		{Reason: ImpactReason{Kind: ImpactFormatChanged}, Expected: "format changed"},
+		{Reason: ImpactReason{Kind: ImpactNowInvalid, Cause: "invalid post code"}, Expected: "now invalid: invalid post code"},
+		{Reason: ImpactReason{Kind: ImpactNowValid, Cause: "invalid locality"}, Expected: "now valid: invalid locality"},
+		{Reason: ImpactReason{Kind: ImpactValidationChanged, Cause: "invalid post code"}, Expected: "validation changed: invalid post code"},
+	}
+
+	for i, testCase := range testCases {
+		if s := testCase.Reason.String(); s != testCase.Expected {
+			t.Errorf("Expected %q for test case %d, got %q", testCase.Expected, i, s)
+		}
+	}
+}
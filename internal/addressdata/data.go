@@ -0,0 +1,77 @@
+// Package addressdata processes the address data of Google's Address Data Service and encodes it in the compact
+// binary format embedded in the address package. It is used by the address package to load snapshots of the data and
+// decode the compiled in data, and by the generator to generate the compiled in data, so that there is a single
+// implementation of both.
+package addressdata
+
+// FieldSet is a set of address fields, with bit i set if the address.Field with the value i is in the set.
+type FieldSet uint
+
+// Country is the address data of a country. The name types are the values of the corresponding address.FieldName, or
+// 0 if the country does not have one.
+type Country struct {
+	ID   string
+	Name string
+
+	DefaultLanguage string
+
+	PostCodePrefix string
+	PostCodeRegex  PostCodeRegex
+	PostURL        string
+
+	Format          string
+	LatinizedFormat string
+
+	AdministrativeAreaNameType int
+	LocalityNameType           int
+	DependentLocalityNameType  int
+	PostCodeNameType           int
+
+	AllowedFields  FieldSet
+	RequiredFields FieldSet
+	Upper          FieldSet
+
+	AdministrativeAreas         map[string][]AdministrativeArea
+	AdministrativeAreaRedirects []AdministrativeAreaRedirect
+}
+
+// PostCodeRegex is the post code regex of a country or subdivision, along with the regexes of its subdivisions keyed
+// by their IDs.
+type PostCodeRegex struct {
+	Regex            string
+	Examples         []string
+	SubdivisionRegex map[string]PostCodeRegex
+}
+
+type AdministrativeArea struct {
+	ID            string
+	Name          string
+	PostalKey     string
+	ISOID         string
+	LatinizedName string
+
+	Localities []Locality
+}
+
+type Locality struct {
+	ID            string
+	Name          string
+	LatinizedName string
+
+	DependentLocalities []DependentLocality
+}
+
+type DependentLocality struct {
+	ID            string
+	Name          string
+	LatinizedName string
+}
+
+// AdministrativeAreaRedirect is an administrative area that is also a country, such as Hong Kong in China.
+type AdministrativeAreaRedirect struct {
+	ID            string
+	Name          string
+	PostalKey     string
+	LatinizedName string
+	CountryCode   string
+}
@@ -0,0 +1,453 @@
+package addressdata
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+)
+
+// The data is encoded in a compact binary format, so that the address package does not need to initialize it when the
+// program starts and each country is only decoded the first time it is used. Changes to the format must increment the
+// version.
+//
+// All integers are unsigned varints and strings are prefixed with their length in bytes. Maps are sorted by their keys
+// and field sets are encoded as their bits.
+//
+//	data:               "ADDR", version, count, count × index entry, countries
+//	index entry:        code, Name, DefaultLanguage, offset of the country, length of the country
+//	country:            ID, Name, DefaultLanguage, PostCodePrefix, PostURL, Format, LatinizedFormat, postCodeRegex,
+//	                    AdministrativeAreaNameType, LocalityNameType, DependentLocalityNameType, PostCodeNameType,
+//	                    AllowedFields, RequiredFields, Upper,
+//	                    count, count × (language, count, count × administrativeArea),
+//	                    count, count × (ID, Name, PostalKey, LatinizedName, CountryCode)
+//	postCodeRegex:      regex, count, count × example, count, count × (ID, postCodeRegex)
+//	administrativeArea: ID, Name, PostalKey, ISOID, LatinizedName, count, count × locality
+//	locality:           ID, Name, LatinizedName, count, count × (ID, Name, LatinizedName)
+//
+// The offsets are relative to the end of the index. Empty maps and slices are decoded as nil, except for the lists of
+// administrative areas in a language, which are decoded as empty slices.
+
+const (
+	encodedDataMagic   = "ADDR"
+	encodedDataVersion = 4
+)
+
+// ErrCorruptData is returned when encoded data cannot be decoded.
+var ErrCorruptData = errors.New("corrupt encoded address data")
+
+// IndexEntry is the entry of a country in the index of the encoded data. The offset and length locate the country in
+// the encoded countries returned by DecodeIndex.
+type IndexEntry struct {
+	Name            string
+	DefaultLanguage string
+	Offset          int
+	Length          int
+}
+
+// Encode encodes the countries, preceded by an index containing the code, name, default language and location of
+// each country, so that the countries can be decoded individually.
+func Encode(countries map[string]Country) []byte {
+
+	index := &dataEncoder{}
+	body := &dataEncoder{}
+
+	index.WriteString(encodedDataMagic)
+	index.uint(encodedDataVersion)
+	index.uint(len(countries))
+
+	for _, code := range sortedKeys(countries) {
+
+		c := countries[code]
+
+		encoded := &dataEncoder{}
+		encoded.country(c)
+
+		index.string(code)
+		index.string(c.Name)
+		index.string(c.DefaultLanguage)
+		index.uint(body.Len())
+		index.uint(encoded.Len())
+
+		body.Write(encoded.Bytes())
+	}
+
+	return append(index.Bytes(), body.Bytes()...)
+}
+
+// DecodeIndex decodes the index of the encoded data, keyed by country code, and returns it along with the encoded
+// countries, which can be decoded individually using DecodeCountry.
+func DecodeIndex(encoded []byte) (map[string]IndexEntry, []byte, error) {
+
+	decoder := dataDecoder{buf: encoded}
+
+	if magic := decoder.bytes(len(encodedDataMagic)); string(magic) != encodedDataMagic {
+		return nil, nil, fmt.Errorf("%w: invalid header", ErrCorruptData)
+	}
+
+	if version := decoder.uint(); version != encodedDataVersion {
+		return nil, nil, fmt.Errorf("%w: unsupported version %d", ErrCorruptData, version)
+	}
+
+	count := decoder.length()
+
+	index := make(map[string]IndexEntry, count)
+
+	for i := 0; i < count && decoder.err == nil; i++ {
+		index[decoder.string()] = IndexEntry{
+			Name:            decoder.string(),
+			DefaultLanguage: decoder.string(),
+			Offset:          decoder.uint(),
+			Length:          decoder.uint(),
+		}
+	}
+
+	if decoder.err != nil {
+		return nil, nil, decoder.err
+	}
+
+	countries := decoder.buf
+
+	for code, entry := range index {
+		if entry.Offset+entry.Length > len(countries) || entry.Offset+entry.Length < entry.Offset {
+			return nil, nil, fmt.Errorf("%w: country %s is out of bounds", ErrCorruptData, code)
+		}
+	}
+
+	return index, countries, nil
+}
+
+// Decode decodes all the countries in the encoded data, keyed by country code.
+func Decode(encoded []byte) (map[string]Country, error) {
+
+	index, body, err := DecodeIndex(encoded)
+
+	if err != nil {
+		return nil, err
+	}
+
+	countries := make(map[string]Country, len(index))
+
+	for code, entry := range index {
+
+		c, err := DecodeCountry(body[entry.Offset : entry.Offset+entry.Length])
+
+		if err != nil {
+			return nil, fmt.Errorf("error decoding %s: %w", code, err)
+		}
+
+		countries[code] = c
+	}
+
+	return countries, nil
+}
+
+// DecodeCountry decodes a country located using its entry in the index.
+func DecodeCountry(encoded []byte) (Country, error) {
+
+	decoder := dataDecoder{buf: encoded}
+
+	c := Country{
+		ID:              decoder.string(),
+		Name:            decoder.string(),
+		DefaultLanguage: decoder.string(),
+		PostCodePrefix:  decoder.string(),
+		PostURL:         decoder.string(),
+		Format:          decoder.string(),
+		LatinizedFormat: decoder.string(),
+		PostCodeRegex:   decoder.postCodeRegex(),
+
+		AdministrativeAreaNameType: decoder.uint(),
+		LocalityNameType:           decoder.uint(),
+		DependentLocalityNameType:  decoder.uint(),
+		PostCodeNameType:           decoder.uint(),
+
+		AllowedFields:  FieldSet(decoder.uint()),
+		RequiredFields: FieldSet(decoder.uint()),
+		Upper:          FieldSet(decoder.uint()),
+	}
+
+	if count := decoder.length(); count > 0 {
+
+		c.AdministrativeAreas = make(map[string][]AdministrativeArea, count)
+
+		for i := 0; i < count && decoder.err == nil; i++ {
+
+			language := decoder.string()
+			adminAreas := make([]AdministrativeArea, decoder.length())
+
+			for j := range adminAreas {
+				adminAreas[j] = decoder.administrativeArea()
+			}
+
+			c.AdministrativeAreas[language] = adminAreas
+		}
+	}
+
+	if count := decoder.length(); count > 0 {
+
+		c.AdministrativeAreaRedirects = make([]AdministrativeAreaRedirect, count)
+
+		for i := range c.AdministrativeAreaRedirects {
+			c.AdministrativeAreaRedirects[i] = AdministrativeAreaRedirect{
+				ID:            decoder.string(),
+				Name:          decoder.string(),
+				PostalKey:     decoder.string(),
+				LatinizedName: decoder.string(),
+				CountryCode:   decoder.string(),
+			}
+		}
+	}
+
+	if decoder.err == nil && len(decoder.buf) > 0 {
+		decoder.err = fmt.Errorf("%w: %d trailing bytes", ErrCorruptData, len(decoder.buf))
+	}
+
+	return c, decoder.err
+}
+
+type dataEncoder struct {
+	bytes.Buffer
+}
+
+func (e *dataEncoder) uint(value int) {
+	e.Write(binary.AppendUvarint(nil, uint64(value)))
+}
+
+func (e *dataEncoder) string(value string) {
+	e.uint(len(value))
+	e.WriteString(value)
+}
+
+func (e *dataEncoder) country(c Country) {
+
+	e.string(c.ID)
+	e.string(c.Name)
+	e.string(c.DefaultLanguage)
+	e.string(c.PostCodePrefix)
+	e.string(c.PostURL)
+	e.string(c.Format)
+	e.string(c.LatinizedFormat)
+	e.postCodeRegex(c.PostCodeRegex)
+
+	e.uint(c.AdministrativeAreaNameType)
+	e.uint(c.LocalityNameType)
+	e.uint(c.DependentLocalityNameType)
+	e.uint(c.PostCodeNameType)
+
+	e.uint(int(c.AllowedFields))
+	e.uint(int(c.RequiredFields))
+	e.uint(int(c.Upper))
+
+	e.uint(len(c.AdministrativeAreas))
+
+	for _, language := range sortedKeys(c.AdministrativeAreas) {
+
+		adminAreas := c.AdministrativeAreas[language]
+
+		e.string(language)
+		e.uint(len(adminAreas))
+
+		for _, adminArea := range adminAreas {
+			e.administrativeArea(adminArea)
+		}
+	}
+
+	e.uint(len(c.AdministrativeAreaRedirects))
+
+	for _, redirect := range c.AdministrativeAreaRedirects {
+		e.string(redirect.ID)
+		e.string(redirect.Name)
+		e.string(redirect.PostalKey)
+		e.string(redirect.LatinizedName)
+		e.string(redirect.CountryCode)
+	}
+}
+
+func (e *dataEncoder) postCodeRegex(p PostCodeRegex) {
+
+	e.string(p.Regex)
+	e.uint(len(p.Examples))
+
+	for _, example := range p.Examples {
+		e.string(example)
+	}
+
+	e.uint(len(p.SubdivisionRegex))
+
+	for _, id := range sortedKeys(p.SubdivisionRegex) {
+		e.string(id)
+		e.postCodeRegex(p.SubdivisionRegex[id])
+	}
+}
+
+func (e *dataEncoder) administrativeArea(a AdministrativeArea) {
+
+	e.string(a.ID)
+	e.string(a.Name)
+	e.string(a.PostalKey)
+	e.string(a.ISOID)
+	e.string(a.LatinizedName)
+	e.uint(len(a.Localities))
+
+	for _, l := range a.Localities {
+
+		e.string(l.ID)
+		e.string(l.Name)
+		e.string(l.LatinizedName)
+		e.uint(len(l.DependentLocalities))
+
+		for _, d := range l.DependentLocalities {
+			e.string(d.ID)
+			e.string(d.Name)
+			e.string(d.LatinizedName)
+		}
+	}
+}
+
+// dataDecoder reads the values in the encoded data. After an error, it returns zero values and keeps the first error.
+type dataDecoder struct {
+	buf []byte
+	err error
+}
+
+func (d *dataDecoder) uint() int {
+
+	if d.err != nil {
+		return 0
+	}
+
+	value, n := binary.Uvarint(d.buf)
+
+	if n <= 0 || value > math.MaxInt32 {
+		d.err = fmt.Errorf("%w: invalid integer", ErrCorruptData)
+		return 0
+	}
+
+	d.buf = d.buf[n:]
+
+	return int(value)
+}
+
+func (d *dataDecoder) bytes(n int) []byte {
+
+	if d.err != nil {
+		return nil
+	}
+
+	if n > len(d.buf) {
+		d.err = fmt.Errorf("%w: unexpected end of data", ErrCorruptData)
+		return nil
+	}
+
+	b := d.buf[:n]
+	d.buf = d.buf[n:]
+
+	return b
+}
+
+func (d *dataDecoder) string() string {
+	return string(d.bytes(d.uint()))
+}
+
+// length reads the number of elements in a list and checks that the data is long enough to hold them, so that corrupt
+// data does not cause huge allocations.
+func (d *dataDecoder) length() int {
+
+	n := d.uint()
+
+	if n > len(d.buf) {
+		d.err = fmt.Errorf("%w: unexpected end of data", ErrCorruptData)
+		return 0
+	}
+
+	return n
+}
+
+func (d *dataDecoder) postCodeRegex() PostCodeRegex {
+
+	p := PostCodeRegex{
+		Regex: d.string(),
+	}
+
+	if count := d.length(); count > 0 {
+
+		p.Examples = make([]string, count)
+
+		for i := range p.Examples {
+			p.Examples[i] = d.string()
+		}
+	}
+
+	if count := d.length(); count > 0 {
+
+		p.SubdivisionRegex = make(map[string]PostCodeRegex, count)
+
+		for i := 0; i < count && d.err == nil; i++ {
+			p.SubdivisionRegex[d.string()] = d.postCodeRegex()
+		}
+	}
+
+	return p
+}
+
+func (d *dataDecoder) administrativeArea() AdministrativeArea {
+
+	a := AdministrativeArea{
+		ID:            d.string(),
+		Name:          d.string(),
+		PostalKey:     d.string(),
+		ISOID:         d.string(),
+		LatinizedName: d.string(),
+	}
+
+	if count := d.length(); count > 0 {
+
+		a.Localities = make([]Locality, count)
+
+		for i := range a.Localities {
+			a.Localities[i] = d.locality()
+		}
+	}
+
+	return a
+}
+
+func (d *dataDecoder) locality() Locality {
+
+	l := Locality{
+		ID:            d.string(),
+		Name:          d.string(),
+		LatinizedName: d.string(),
+	}
+
+	if count := d.length(); count > 0 {
+
+		l.DependentLocalities = make([]DependentLocality, count)
+
+		for i := range l.DependentLocalities {
+			l.DependentLocalities[i] = DependentLocality{
+				ID:            d.string(),
+				Name:          d.string(),
+				LatinizedName: d.string(),
+			}
+		}
+	}
+
+	return l
+}
+
+func sortedKeys[T any](m map[string]T) []string {
+
+	keys := make([]string, 0, len(m))
+
+	for key := range m {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
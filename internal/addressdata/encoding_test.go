@@ -0,0 +1,103 @@
+package addressdata
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestDecode(t *testing.T) {
+
+	encoded, err := os.ReadFile("../../data.generated.bin")
+
+	if err != nil {
+		t.Fatalf("Unexpected error reading the generated data: %s", err)
+	}
+
+	countries, err := Decode(encoded)
+
+	if err != nil {
+		t.Fatalf("Unexpected error decoding the generated data: %s", err)
+	}
+
+	if !bytes.Equal(Encode(countries), encoded) {
+		t.Error("Encoding the decoded data does not produce the same data")
+	}
+
+	for i, corrupt := range [][]byte{nil, []byte("ADDX\x01\x00"), []byte("ADDR\x05\x00"), encoded[:len(encoded)/2]} {
+		if _, err := Decode(corrupt); !errors.Is(err, ErrCorruptData) {
+			t.Errorf("Expected ErrCorruptData decoding corrupt data for test case %d, got %v", i, err)
+		}
+	}
+}
+
+func TestEncodeCountry(t *testing.T) {
+
+	testCases := []Country{
+		{},
+		{
+			ID:                         "XA",
+			Name:                       "TEST",
+			DefaultLanguage:            "en",
+			PostCodePrefix:             "XA-",
+			PostURL:                    "https://example.com",
+			Format:                     "%N%n%O%n%A%n%C %S %Z",
+			LatinizedFormat:            "%N%n%O%n%A%n%C %S %Z",
+			AdministrativeAreaNameType: 18,
+			PostCodeNameType:           22,
+			AllowedFields:              1<<2 | 1<<3 | 1<<4 | 1<<6 | 1<<7 | 1<<8,
+			RequiredFields:             1<<4 | 1<<6,
+			Upper:                      1 << 6,
+			PostCodeRegex: PostCodeRegex{
+				Regex:    `^(\d{5})$`,
+				Examples: []string{"12345"},
+				SubdivisionRegex: map[string]PostCodeRegex{
+					"A": {Regex: "^1", SubdivisionRegex: map[string]PostCodeRegex{"B": {Regex: "^12"}}},
+				},
+			},
+			AdministrativeAreas: map[string][]AdministrativeArea{
+				"en": {
+					{
+						ID:            "A",
+						Name:          "Area",
+						PostalKey:     "A",
+						ISOID:         "A",
+						LatinizedName: "Area",
+						Localities: []Locality{
+							{ID: "B", Name: "Bee", DependentLocalities: []DependentLocality{{ID: "C", Name: "Sea"}}},
+						},
+					},
+				},
+				"fr": {},
+			},
+			AdministrativeAreaRedirects: []AdministrativeAreaRedirect{
+				{ID: "91", Name: "Redirect", PostalKey: "R", LatinizedName: "Redirect", CountryCode: "XB"},
+			},
+		},
+	}
+
+	for i, testCase := range testCases {
+
+		encoded := &dataEncoder{}
+		encoded.country(testCase)
+
+		decoded, err := DecodeCountry(encoded.Bytes())
+
+		if err != nil {
+			t.Errorf("Unexpected error decoding test case %d: %s", i, err)
+			continue
+		}
+
+		if !reflect.DeepEqual(decoded, testCase) {
+			t.Errorf("Decoded country for test case %d does not match, expected %+v, got %+v", i, testCase, decoded)
+		}
+
+		for j, corrupt := range [][]byte{encoded.Bytes()[:encoded.Len()-1], append(encoded.Bytes(), 0)} {
+			if _, err := DecodeCountry(corrupt); !errors.Is(err, ErrCorruptData) {
+				t.Errorf("Expected ErrCorruptData decoding corrupt data %d for test case %d, got %v", j, i, err)
+			}
+		}
+	}
+}
@@ -0,0 +1,876 @@
+package addressdata
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/bits"
+	"regexp"
+	"sort"
+	"strings"
+
+	"golang.org/x/text/language"
+)
+
+// FetchFunc returns a JSON document of Google's Address Data Service by its ID, such as data/AU or data/CA--fr.
+type FetchFunc func(id string) ([]byte, error)
+
+var postPrefixFixes = map[string]string{
+	"PR": "PR ",
+}
+
+// redirectCountryCodes contains the codes of countries that are also subdivisions of other countries, where the ISO ID
+// of the subdivision is not the country code.
+var redirectCountryCodes = map[string]string{
+	"CN-71": "TW",
+	"CN-91": "HK",
+	"CN-92": "MO",
+}
+
+var defaultLanguageOverrides = map[string]string{
+	"AQ": "en",
+	"AS": "en",
+	"BQ": "nl",
+	"BV": "nb",
+	"CW": "nl",
+	"DJ": "fr",
+	"GS": "en",
+	"HM": "en",
+	"MV": "en",
+	"PG": "en",
+	"PW": "en",
+	"TK": "en",
+	"VU": "fr",
+	"WS": "en",
+}
+
+// fieldKeys maps Google's one-letter abbreviations of the address fields to the values of the address.Field constants.
+var fieldKeys = map[rune]int{
+	'N': 2,
+	'O': 3,
+	'A': 4,
+	'D': 5,
+	'C': 6,
+	'S': 7,
+	'Z': 8,
+	'X': 9,
+}
+
+// nameTypes maps Google's names for the fields to the values of the address.FieldName constants.
+var nameTypes = map[string]int{
+	"area":             1,
+	"city":             2,
+	"county":           3,
+	"department":       4,
+	"district":         5,
+	"do_si":            6,
+	"eircode":          7,
+	"emirate":          8,
+	"island":           9,
+	"neighborhood":     10,
+	"oblast":           11,
+	"pin":              12,
+	"parish":           13,
+	"post_town":        14,
+	"postal":           15,
+	"prefecture":       16,
+	"province":         17,
+	"state":            18,
+	"suburb":           19,
+	"townland":         20,
+	"village_township": 21,
+	"zip":              22,
+}
+
+var formatFieldRegex = regexp.MustCompile(`%[NOADCSZX]`)
+
+var languageSuffixRegex = regexp.MustCompile(`--.*`)
+
+type countriesJSON struct {
+	Countries string `json:"countries"`
+}
+
+type countryJSON struct {
+	ID  string `json:"id"`
+	Key string `json:"key"`
+
+	Lang      string `json:"lang"`
+	Languages string `json:"languages"`
+	Name      string `json:"name"`
+
+	Fmt  string `json:"fmt"`
+	Lfmt string `json:"lfmt"`
+
+	StateNameType       string `json:"state_name_type"`
+	LocalityNameType    string `json:"locality_name_type"`
+	SubLocalityNameType string `json:"sublocality_name_type"`
+	ZipNameType         string `json:"zip_name_type"`
+
+	Require string `json:"require"`
+	Upper   string `json:"upper"`
+
+	SubISOIDs string `json:"sub_isoids"`
+	SubKeys   string `json:"sub_keys"`
+	SubLNames string `json:"sub_lnames"`
+	SubNames  string `json:"sub_names"`
+
+	SubMores string `json:"sub_mores"`
+
+	SubXRequires string `json:"sub_xrequires"`
+	SubXZips     string `json:"sub_xzips"`
+
+	SubZips   string `json:"sub_zips"`
+	SubZipExs string `json:"sub_zipexs"`
+
+	PostPrefix string `json:"postprefix"`
+	PostURL    string `json:"posturl"`
+	Zip        string `json:"zip"`
+	Zipex      string `json:"zipex"`
+}
+
+type subdivisionJSON struct {
+	ID  string `json:"id"`
+	Key string `json:"key"`
+
+	Name  string `json:"name"`
+	LName string `json:"lname"`
+
+	Lang string `json:"lang"`
+
+	ISOID   string `json:"isoid"`
+	SubKeys string `json:"sub_keys"`
+
+	SubNames   string `json:"sub_names"`
+	SubMores   string `json:"sub_mores"`
+	SubLNames  string `json:"sub_lnames"`
+	SubLFNames string `json:"sub_lfnames"`
+
+	Zip       string `json:"zip"`
+	ZipEx     string `json:"zipex"`
+	SubZips   string `json:"sub_zips"`
+	SubZipExs string `json:"sub_zipexs"`
+}
+
+// CountryCodes returns the codes of the countries in the list of countries, which does not include the fall back ZZ
+// (unknown) country.
+func CountryCodes(fetch FetchFunc) ([]string, error) {
+
+	var countries countriesJSON
+
+	if err := decode(fetch, "data", &countries); err != nil {
+		return nil, err
+	}
+
+	return strings.Split(countries.Countries, "~"), nil
+}
+
+// ProcessCountry fetches the data of a country, including its subdivisions in all its languages, and processes it.
+// The data is sanity checked, including checking the post code regular expressions against the examples in the data.
+// Empty maps and slices are nil, as they are decoded from the encoded data.
+func ProcessCountry(fetch FetchFunc, countryCode string) (Country, error) {
+
+	id := "data/" + countryCode
+
+	var countryData countryJSON
+
+	if err := decode(fetch, id, &countryData); err != nil {
+		return Country{}, err
+	}
+
+	// Sanity check latinized format
+	if countryData.Lfmt != "" && bits.OnesCount(uint(formatFieldSet(countryData.Fmt))) != bits.OnesCount(uint(formatFieldSet(countryData.Lfmt))) {
+		return Country{}, fmt.Errorf("number of fields in the address format and latinized address format does not match for %s", countryCode)
+	}
+
+	// Sanity check post code regex
+	if countryData.Zip != "" {
+		if err := checkPostCodeRegex("^("+countryData.Zip+")$", strings.Split(countryData.Zipex, ",")); err != nil {
+			return Country{}, fmt.Errorf("error validating post code regex for %s: %w", countryCode, err)
+		}
+	}
+
+	result := Country{
+		ID:   countryCode,
+		Name: countryData.Name,
+
+		Format:          countryData.Fmt,
+		LatinizedFormat: countryData.Lfmt,
+
+		AllowedFields:  formatFieldSet(countryData.Fmt),
+		RequiredFields: keyFieldSet(countryData.Require),
+		Upper:          keyFieldSet(countryData.Upper),
+	}
+
+	if countryData.Zip != "" {
+		result.PostCodeRegex.Regex = "^(" + countryData.Zip + ")$"
+		result.PostCodeRegex.Examples = postCodeExamples(countryData.Zipex)
+	}
+
+	if countryData.Lang != "" {
+		result.DefaultLanguage = countryData.Lang
+	} else if lang, ok := defaultLanguageOverrides[countryCode]; ok {
+		result.DefaultLanguage = lang
+	} else {
+		lang, _ := language.Make("und-" + countryCode).Base()
+		result.DefaultLanguage = lang.String()
+	}
+
+	countryNameTypes := []struct {
+		name     string
+		nameType *int
+	}{
+		{countryData.StateNameType, &result.AdministrativeAreaNameType},
+		{countryData.LocalityNameType, &result.LocalityNameType},
+		{countryData.SubLocalityNameType, &result.DependentLocalityNameType},
+		{countryData.ZipNameType, &result.PostCodeNameType},
+	}
+
+	for _, nameType := range countryNameTypes {
+
+		if nameType.name == "" {
+			continue
+		}
+
+		fieldName, ok := nameTypes[nameType.name]
+
+		if !ok {
+			return Country{}, fmt.Errorf("unknown field name %s for %s", nameType.name, countryCode)
+		}
+
+		*nameType.nameType = fieldName
+	}
+
+	if prefix, ok := postPrefixFixes[countryCode]; ok {
+		result.PostCodePrefix = prefix
+	} else {
+		result.PostCodePrefix = countryData.PostPrefix
+	}
+
+	result.PostURL = countryData.PostURL
+
+	if countryData.SubKeys != "" {
+
+		// Sanity check
+		if countryData.Languages == "" {
+			return Country{}, fmt.Errorf("%s has subkeys but does not have any languages", countryCode)
+		}
+
+		result.AdministrativeAreas = map[string][]AdministrativeArea{}
+
+		languages := strings.Split(countryData.Languages, "~")
+
+		for _, lang := range languages {
+
+			languageJSON := countryData
+			languageSuffix := ""
+
+			// The default language is in the country's data, while other languages are fetched separately
+			isDefault := len(languages) == 1 || lang == countryData.Lang
+
+			if !isDefault {
+
+				languageSuffix = lang
+				languageJSON = countryJSON{}
+
+				if err := decode(fetch, id+"--"+lang, &languageJSON); err != nil {
+					return Country{}, err
+				}
+			}
+
+			adminAreas, postCodeRegex, err := processAdministrativeAreas(fetch, languageJSON, languageSuffix)
+
+			if err != nil {
+				return Country{}, fmt.Errorf("error processing admin areas in language %s for country %s: %w", lang, countryCode, err)
+			}
+
+			if isDefault {
+				result.PostCodeRegex.SubdivisionRegex = postCodeRegex
+				result.AdministrativeAreaRedirects = processAdministrativeAreaRedirects(languageJSON)
+			}
+
+			for lang, areas := range adminAreas {
+				result.AdministrativeAreas[lang] = areas
+			}
+		}
+	}
+
+	return normalizeCountry(result), nil
+}
+
+func decode(fetch FetchFunc, id string, v any) error {
+
+	contents, err := fetch(id)
+
+	if err != nil {
+		return fmt.Errorf("error getting %s: %w", id, err)
+	}
+
+	if err := json.Unmarshal(contents, v); err != nil {
+		return fmt.Errorf("error decoding %s: %w", id, err)
+	}
+
+	return nil
+}
+
+func processAdministrativeAreas(fetch FetchFunc, countryJSON countryJSON, language string) (map[string][]AdministrativeArea, map[string]PostCodeRegex, error) {
+
+	result := map[string][]AdministrativeArea{}
+	postCodeResult := map[string]PostCodeRegex{}
+
+	subISOIDs := strings.Split(countryJSON.SubISOIDs, "~")
+	subNames := strings.Split(countryJSON.SubNames, "~")
+	subZips := strings.Split(countryJSON.SubZips, "~")
+	subMores := strings.Split(countryJSON.SubMores, "~")
+	subKeys := strings.Split(countryJSON.SubKeys, "~")
+	subZipExs := strings.Split(countryJSON.SubZipExs, "~")
+	subLNames := strings.Split(countryJSON.SubLNames, "~")
+
+	// Subdivisions that are also countries (such as Hong Kong in China) have special post code regexes or required
+	// fields, so they are skipped and kept as redirects by processAdministrativeAreaRedirects instead
+	subdivisionsToSkip := map[string]struct{}{}
+
+	for _, exceptions := range []string{countryJSON.SubXRequires, countryJSON.SubXZips} {
+
+		if exceptions == "" {
+			continue
+		}
+
+		for i, exception := range strings.Split(exceptions, "~") {
+			if exception != "" && i < len(subISOIDs) {
+				subdivisionsToSkip[subISOIDs[i]] = struct{}{}
+			}
+		}
+	}
+
+	var processedAdministrativeAreas []AdministrativeArea
+	var latinizedAdministrativeAreas []AdministrativeArea
+
+	// Sub keys are used when the ISO IDs are missing (such as in ES) and for the US, where valid addresses can use
+	// administrative areas without ISO IDs (such as the military AA, AE and AP)
+	useSubKeys := countryJSON.SubISOIDs == "" || countryJSON.Key == "US"
+
+	ids := subISOIDs
+
+	if useSubKeys {
+		ids = subKeys
+	}
+
+	for i, isoID := range ids {
+
+		if isoID == "" {
+			if !useSubKeys {
+				// Skip administrative areas without ISO IDs, as they are contested or not recognized
+				continue
+			}
+
+			isoID = subKeys[i]
+		}
+
+		if _, ok := subdivisionsToSkip[isoID]; ok {
+			continue
+		}
+
+		// Sanity check
+		if countryJSON.SubZips != "" && countryJSON.SubZipExs != "" && subZips[i] != "" && subZipExs[i] != "" {
+			if err := checkPostCodeRegex("^"+subZips[i], strings.Split(subZipExs[i], ",")); err != nil {
+				return nil, nil, fmt.Errorf("error checking administrative area post code regex for %s / %s against sample: %w", isoID, countryJSON.Key, err)
+			}
+		}
+
+		adminArea := AdministrativeArea{
+			ID:        isoID,
+			Name:      subKeys[i],
+			PostalKey: subKeys[i],
+			ISOID:     subdivisionISOID(subISOIDs, i),
+		}
+
+		if countryJSON.SubNames != "" {
+			adminArea.Name = subNames[i]
+		}
+
+		if countryJSON.SubLNames != "" {
+			adminArea.LatinizedName = subLNames[i]
+		}
+
+		if countryJSON.SubZips != "" && subZips[i] != "" {
+			postCodeResult[isoID] = PostCodeRegex{
+				Regex:    "^" + subZips[i],
+				Examples: subdivisionPostCodeExamples(subZipExs, i),
+			}
+		}
+
+		var latinizedAdminArea AdministrativeArea
+
+		if countryJSON.SubLNames != "" {
+			latinizedAdminArea = AdministrativeArea{
+				ID:            isoID,
+				Name:          subLNames[i],
+				PostalKey:     subKeys[i],
+				ISOID:         adminArea.ISOID,
+				LatinizedName: subLNames[i],
+			}
+		}
+
+		if countryJSON.SubMores != "" && subMores[i] == "true" {
+
+			var adminAreaJSON subdivisionJSON
+
+			if err := decode(fetch, subdivisionID(countryJSON.ID, subKeys[i], language), &adminAreaJSON); err != nil {
+				return nil, nil, err
+			}
+
+			localities, subPostCodeRegex, err := processLocalities(fetch, adminAreaJSON, language)
+
+			if err != nil {
+				return nil, nil, fmt.Errorf("error processing localities for %s/%s: %w", countryJSON.Key, subKeys[i], err)
+			}
+
+			if len(subPostCodeRegex) > 0 {
+
+				// Sanity check
+				postCodeRegex, ok := postCodeResult[isoID]
+
+				if !ok {
+					return nil, nil, fmt.Errorf("locality %s has postcode regexes, but the parent locality does not", adminAreaJSON.ID)
+				}
+
+				postCodeRegex.SubdivisionRegex = subPostCodeRegex
+				postCodeResult[isoID] = postCodeRegex
+			}
+
+			adminArea.Localities = localities[countryJSON.Lang]
+
+			// Latinized names are considered to be English
+			if adminAreaJSON.SubLNames != "" {
+
+				// Sanity check
+				if _, ok := localities["en"]; !ok {
+					return nil, nil, fmt.Errorf("%s has latinized admin areas, but does not have any latinized localities for %s", countryJSON.Key, adminAreaJSON.ID)
+				}
+
+				latinizedAdminArea.Localities = localities["en"]
+			}
+		}
+
+		processedAdministrativeAreas = append(processedAdministrativeAreas, adminArea)
+
+		if latinizedAdminArea.ID != "" {
+			latinizedAdministrativeAreas = append(latinizedAdministrativeAreas, latinizedAdminArea)
+		}
+	}
+
+	result[countryJSON.Lang] = processedAdministrativeAreas
+
+	if len(latinizedAdministrativeAreas) > 0 {
+
+		// Sanity check
+		if len(latinizedAdministrativeAreas) != len(processedAdministrativeAreas) {
+			return nil, nil, fmt.Errorf("number of latinized admin areas (%d) does not match number of admin areas (%d) for %s", len(latinizedAdministrativeAreas), len(processedAdministrativeAreas), countryJSON.ID)
+		}
+
+		sort.Slice(latinizedAdministrativeAreas, func(i, j int) bool {
+			return latinizedAdministrativeAreas[i].Name < latinizedAdministrativeAreas[j].Name
+		})
+
+		result["en"] = latinizedAdministrativeAreas
+	}
+
+	return result, postCodeResult, nil
+}
+
+// processAdministrativeAreaRedirects returns the administrative areas that are skipped by processAdministrativeAreas
+// because they are also countries, so that addresses using them can be redirected to the country. Administrative
+// areas without a known country code are left out.
+func processAdministrativeAreaRedirects(countryJSON countryJSON) []AdministrativeAreaRedirect {
+
+	subISOIDs := strings.Split(countryJSON.SubISOIDs, "~")
+	subNames := strings.Split(countryJSON.SubNames, "~")
+	subKeys := strings.Split(countryJSON.SubKeys, "~")
+	subLNames := strings.Split(countryJSON.SubLNames, "~")
+	subXRequires := strings.Split(countryJSON.SubXRequires, "~")
+	subXZips := strings.Split(countryJSON.SubXZips, "~")
+
+	useSubKeys := countryJSON.SubISOIDs == "" || countryJSON.Key == "US"
+
+	var redirects []AdministrativeAreaRedirect
+
+	for i, key := range subKeys {
+
+		if (i >= len(subXRequires) || subXRequires[i] == "") && (i >= len(subXZips) || subXZips[i] == "") {
+			continue
+		}
+
+		id := subdivisionISOID(subISOIDs, i)
+
+		if useSubKeys {
+			id = key
+		}
+
+		countryCode := redirectCountryCode(countryJSON.Key, id)
+
+		if countryCode == "" {
+			continue
+		}
+
+		redirect := AdministrativeAreaRedirect{
+			ID:          id,
+			Name:        key,
+			PostalKey:   key,
+			CountryCode: countryCode,
+		}
+
+		if countryJSON.SubNames != "" {
+			redirect.Name = subNames[i]
+		}
+
+		if countryJSON.SubLNames != "" {
+			redirect.LatinizedName = subLNames[i]
+		}
+
+		redirects = append(redirects, redirect)
+	}
+
+	return redirects
+}
+
+// redirectCountryCode returns the code of the country that a subdivision of another country is also, or an empty
+// string if it is not known. Subdivisions such as Puerto Rico in the US use the country code as their ID.
+func redirectCountryCode(countryCode, id string) string {
+
+	if code, ok := redirectCountryCodes[countryCode+"-"+id]; ok {
+		return code
+	}
+
+	if region, err := language.ParseRegion(id); err == nil && region.IsCountry() && region.String() == id {
+		return id
+	}
+
+	return ""
+}
+
+func processLocalities(fetch FetchFunc, adminAreaJSON subdivisionJSON, language string) (map[string][]Locality, map[string]PostCodeRegex, error) {
+
+	result := map[string][]Locality{}
+	postCodeResult := map[string]PostCodeRegex{}
+
+	subKeys := strings.Split(adminAreaJSON.SubKeys, "~")
+	subNames := strings.Split(adminAreaJSON.SubNames, "~")
+	subMores := strings.Split(adminAreaJSON.SubMores, "~")
+	subZips := strings.Split(adminAreaJSON.SubZips, "~")
+	subZipExs := strings.Split(adminAreaJSON.SubZipExs, "~")
+	subLNames := strings.Split(adminAreaJSON.SubLNames, "~")
+
+	var processedLocalities []Locality
+	var latinizedLocalities []Locality
+
+	for i, key := range subKeys {
+
+		// Sanity check
+		if adminAreaJSON.SubZips != "" && adminAreaJSON.SubZipExs != "" && subZips[i] != "" && subZipExs[i] != "" {
+			if err := checkPostCodeRegex("^"+subZips[i], strings.Split(subZipExs[i], ",")); err != nil {
+				return nil, nil, fmt.Errorf("error checking locality post code regex for %s against sample: %w", adminAreaJSON.ID, err)
+			}
+		}
+
+		// There are no ISO IDs at this level, so the key is used as the ID
+		loc := Locality{
+			ID:   key,
+			Name: key,
+		}
+
+		if adminAreaJSON.SubNames != "" {
+			loc.Name = subNames[i]
+		}
+
+		if adminAreaJSON.SubLNames != "" {
+			loc.LatinizedName = subLNames[i]
+		}
+
+		if adminAreaJSON.SubZips != "" && subZips[i] != "" {
+			postCodeResult[key] = PostCodeRegex{
+				Regex:    "^" + subZips[i],
+				Examples: subdivisionPostCodeExamples(subZipExs, i),
+			}
+		}
+
+		var latinizedLocality Locality
+
+		if adminAreaJSON.SubLNames != "" {
+			latinizedLocality = Locality{
+				ID:            key,
+				Name:          subLNames[i],
+				LatinizedName: subLNames[i],
+			}
+		}
+
+		if adminAreaJSON.SubMores != "" && subMores[i] == "true" {
+
+			var localityJSON subdivisionJSON
+
+			if err := decode(fetch, subdivisionID(adminAreaJSON.ID, key, language), &localityJSON); err != nil {
+				return nil, nil, err
+			}
+
+			dependentLocalities, subPostCodeRegex, err := processDependentLocalities(localityJSON)
+
+			if err != nil {
+				return nil, nil, fmt.Errorf("error processing dependent localities for %s/%s: %w", adminAreaJSON.ID, key, err)
+			}
+
+			if len(subPostCodeRegex) > 0 {
+
+				// Sanity check
+				postCodeRegex, ok := postCodeResult[key]
+
+				if !ok {
+					return nil, nil, fmt.Errorf("dependent locality %s/%s has postcode regexes, but the parent locality does not", adminAreaJSON.ID, key)
+				}
+
+				postCodeRegex.SubdivisionRegex = subPostCodeRegex
+				postCodeResult[key] = postCodeRegex
+			}
+
+			loc.DependentLocalities = dependentLocalities[adminAreaJSON.Lang]
+
+			// Latinized names are considered to be English
+			if adminAreaJSON.SubLNames != "" {
+
+				// Sanity check
+				if _, ok := dependentLocalities["en"]; !ok {
+					return nil, nil, fmt.Errorf("%s has latinized localities, but does not have any latinized dependent localities for %s", adminAreaJSON.ID, key)
+				}
+
+				latinizedLocality.DependentLocalities = dependentLocalities["en"]
+			}
+		}
+
+		processedLocalities = append(processedLocalities, loc)
+
+		if adminAreaJSON.SubLNames != "" {
+			latinizedLocalities = append(latinizedLocalities, latinizedLocality)
+		}
+	}
+
+	result[adminAreaJSON.Lang] = processedLocalities
+
+	if len(latinizedLocalities) > 0 {
+
+		// Sanity check
+		if len(latinizedLocalities) != len(processedLocalities) {
+			return nil, nil, fmt.Errorf("number of latinized localities (%d) does not match number of localities (%d) for %s", len(latinizedLocalities), len(processedLocalities), adminAreaJSON.ID)
+		}
+
+		sort.Slice(latinizedLocalities, func(i, j int) bool {
+			return latinizedLocalities[i].Name < latinizedLocalities[j].Name
+		})
+
+		result["en"] = latinizedLocalities
+	}
+
+	return result, postCodeResult, nil
+}
+
+func processDependentLocalities(localityJSON subdivisionJSON) (map[string][]DependentLocality, map[string]PostCodeRegex, error) {
+
+	result := map[string][]DependentLocality{}
+	postCodeResult := map[string]PostCodeRegex{}
+
+	subKeys := strings.Split(localityJSON.SubKeys, "~")
+	subNames := strings.Split(localityJSON.SubNames, "~")
+	subZips := strings.Split(localityJSON.SubZips, "~")
+	subZipExs := strings.Split(localityJSON.SubZipExs, "~")
+	subLNames := strings.Split(localityJSON.SubLNames, "~")
+
+	var processedDependentLocalities []DependentLocality
+
+	for i, key := range subKeys {
+
+		// Sanity check
+		if localityJSON.SubZips != "" && localityJSON.SubZipExs != "" && subZips[i] != "" && subZipExs[i] != "" {
+			if err := checkPostCodeRegex("^"+subZips[i], strings.Split(subZipExs[i], ",")); err != nil {
+				return nil, nil, fmt.Errorf("error checking dependent locality post code regex for %s against sample: %w", localityJSON.ID, err)
+			}
+		}
+
+		// There are no ISO IDs at this level, so the key is used as the ID
+		dependentLoc := DependentLocality{
+			ID:   key,
+			Name: key,
+		}
+
+		if localityJSON.SubNames != "" {
+			dependentLoc.Name = subNames[i]
+		}
+
+		if localityJSON.SubLNames != "" {
+			dependentLoc.LatinizedName = subLNames[i]
+		}
+
+		if localityJSON.SubZips != "" && subZips[i] != "" {
+			postCodeResult[key] = PostCodeRegex{
+				Regex:    "^" + subZips[i],
+				Examples: subdivisionPostCodeExamples(subZipExs, i),
+			}
+		}
+
+		processedDependentLocalities = append(processedDependentLocalities, dependentLoc)
+	}
+
+	result[localityJSON.Lang] = processedDependentLocalities
+
+	// Latinized names are considered to be English
+	if localityJSON.SubLNames != "" {
+
+		var latinizedDependentLocalities []DependentLocality
+
+		for i, key := range subKeys {
+			latinizedDependentLocalities = append(latinizedDependentLocalities, DependentLocality{
+				ID:            key,
+				Name:          subLNames[i],
+				LatinizedName: subLNames[i],
+			})
+		}
+
+		sort.Slice(latinizedDependentLocalities, func(i, j int) bool {
+			return latinizedDependentLocalities[i].Name < latinizedDependentLocalities[j].Name
+		})
+
+		result["en"] = latinizedDependentLocalities
+	}
+
+	return result, postCodeResult, nil
+}
+
+// subdivisionID returns the ID of a subdivision's data, which is the ID of its parent without the language suffix,
+// followed by its key and the language suffix, if any.
+func subdivisionID(parentID, key, language string) string {
+
+	id := languageSuffixRegex.ReplaceAllString(parentID, "") + "/" + key
+
+	if language != "" {
+		id += "--" + language
+	}
+
+	return id
+}
+
+// normalizeCountry replaces empty maps and slices with nil, as they are decoded from the encoded data, so that
+// processed data can be compared with decoded data.
+func normalizeCountry(c Country) Country {
+
+	c.PostCodeRegex = normalizePostCodeRegex(c.PostCodeRegex)
+
+	if len(c.AdministrativeAreas) == 0 {
+		c.AdministrativeAreas = nil
+	}
+
+	for lang, adminAreas := range c.AdministrativeAreas {
+
+		if adminAreas == nil {
+			c.AdministrativeAreas[lang] = []AdministrativeArea{}
+		}
+
+		for i := range adminAreas {
+
+			if len(adminAreas[i].Localities) == 0 {
+				adminAreas[i].Localities = nil
+			}
+
+			for j := range adminAreas[i].Localities {
+				if len(adminAreas[i].Localities[j].DependentLocalities) == 0 {
+					adminAreas[i].Localities[j].DependentLocalities = nil
+				}
+			}
+		}
+	}
+
+	return c
+}
+
+func normalizePostCodeRegex(p PostCodeRegex) PostCodeRegex {
+
+	if len(p.SubdivisionRegex) == 0 {
+		p.SubdivisionRegex = nil
+	}
+
+	for id, subdivisionRegex := range p.SubdivisionRegex {
+		p.SubdivisionRegex[id] = normalizePostCodeRegex(subdivisionRegex)
+	}
+
+	return p
+}
+
+// formatFieldSet returns the fields used in an address format, such as %N%n%O%n%A%n%C %S %Z.
+func formatFieldSet(format string) FieldSet {
+
+	var fields FieldSet
+
+	for _, token := range formatFieldRegex.FindAllString(format, -1) {
+		fields |= 1 << fieldKeys[rune(token[1])]
+	}
+
+	return fields
+}
+
+// keyFieldSet returns the fields in a list of Google's one-letter field abbreviations, such as ACSZ.
+func keyFieldSet(keys string) FieldSet {
+
+	var fields FieldSet
+
+	for _, key := range keys {
+		if field, ok := fieldKeys[key]; ok {
+			fields |= 1 << field
+		}
+	}
+
+	return fields
+}
+
+// postCodeExamples returns the sample post codes in a comma-separated list, such as zipex.
+func postCodeExamples(examples string) []string {
+
+	if examples == "" {
+		return nil
+	}
+
+	return strings.Split(examples, ",")
+}
+
+// subdivisionISOID returns the ISO ID of the subdivision at an index of a list of ISO IDs of subdivisions, such as
+// sub_isoids. Some lists are shorter than the list of keys, or empty when a country has no ISO IDs for its subdivisions.
+func subdivisionISOID(subISOIDs []string, i int) string {
+
+	if i >= len(subISOIDs) {
+		return ""
+	}
+
+	return subISOIDs[i]
+}
+
+// subdivisionPostCodeExamples returns the sample post codes of the subdivision at an index of a list of examples for
+// subdivisions, such as sub_zipexs.
+func subdivisionPostCodeExamples(subZipExs []string, i int) []string {
+
+	if i >= len(subZipExs) {
+		return nil
+	}
+
+	return postCodeExamples(subZipExs[i])
+}
+
+// checkPostCodeRegex checks that the post code regex matches all the sample post codes in the data.
+func checkPostCodeRegex(regex string, postCodes []string) error {
+
+	postCodeRegex, err := regexp.Compile(regex)
+
+	if err != nil {
+		return fmt.Errorf("unable to compile post code regex %s: %w", regex, err)
+	}
+
+	for _, postCode := range postCodes {
+		if !postCodeRegex.MatchString(postCode) {
+			return fmt.Errorf("sample post code %s could not be validated by post code regex %s", postCode, regex)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,103 @@
+package addressdata
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestProcessAdministrativeAreaRedirects(t *testing.T) {
+
+	testCases := []struct {
+		Country  countryJSON
+		Expected []AdministrativeAreaRedirect
+	}{
+		{
+			Country: countryJSON{
+				Key:          "CN",
+				SubKeys:      "北京市~台湾~香港",
+				SubLNames:    "Beijing Shi~Taiwan~Hong Kong",
+				SubISOIDs:    "11~71~91",
+				SubXRequires: "~ACS~",
+				SubXZips:     "~~999077",
+			},
+			Expected: []AdministrativeAreaRedirect{
+				{ID: "71", Name: "台湾", PostalKey: "台湾", LatinizedName: "Taiwan", CountryCode: "TW"},
+				{ID: "91", Name: "香港", PostalKey: "香港", LatinizedName: "Hong Kong", CountryCode: "HK"},
+			},
+		},
+		{
+			Country: countryJSON{
+				Key:      "US",
+				SubKeys:  "CA~PR",
+				SubNames: "California~Puerto Rico",
+				SubXZips: "~00[679]",
+			},
+			Expected: []AdministrativeAreaRedirect{
+				{ID: "PR", Name: "Puerto Rico", PostalKey: "PR", CountryCode: "PR"},
+			},
+		},
+		{
+			Country: countryJSON{
+				Key:       "US",
+				SubKeys:   "AL~AA~GU~PR~VI",
+				SubNames:  "Alabama~Armed Forces (AA)~Guam~Puerto Rico~Virgin Islands",
+				SubISOIDs: "AL~~GU~PR~VI",
+			},
+		},
+		{
+			Country: countryJSON{
+				Key:          "XA",
+				SubKeys:      "A~B",
+				SubISOIDs:    "1~2",
+				SubXRequires: "~A",
+			},
+		},
+		{
+			Country: countryJSON{
+				Key:     "AU",
+				SubKeys: "ACT~NSW",
+			},
+		},
+	}
+
+	for i, testCase := range testCases {
+		if redirects := processAdministrativeAreaRedirects(testCase.Country); !reflect.DeepEqual(redirects, testCase.Expected) {
+			t.Errorf("Expected redirects %+v for test case %d, got %+v", testCase.Expected, i, redirects)
+		}
+	}
+}
+
+func TestFieldSets(t *testing.T) {
+
+	testCases := []struct {
+		Format   string
+		Keys     string
+		Expected FieldSet
+	}{
+		{
+			Format:   "%N%n%O%n%A%n%C %S %Z",
+			Keys:     "NOACSZ",
+			Expected: 1<<2 | 1<<3 | 1<<4 | 1<<6 | 1<<7 | 1<<8,
+		},
+		{
+			Format:   "%Z%n%S%C%D%n%A%n%O%n%N%n%X",
+			Keys:     "ZSCDAONX",
+			Expected: 1<<2 | 1<<3 | 1<<4 | 1<<5 | 1<<6 | 1<<7 | 1<<8 | 1<<9,
+		},
+		{
+			Format: "%n",
+			Keys:   "Q",
+		},
+	}
+
+	for i, testCase := range testCases {
+
+		if fields := formatFieldSet(testCase.Format); fields != testCase.Expected {
+			t.Errorf("Expected fields %b in the format for test case %d, got %b", testCase.Expected, i, fields)
+		}
+
+		if fields := keyFieldSet(testCase.Keys); fields != testCase.Expected {
+			t.Errorf("Expected fields %b in the keys for test case %d, got %b", testCase.Expected, i, fields)
+		}
+	}
+}
@@ -0,0 +1,193 @@
+package address
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// LabelLimits restricts the size of a postal label. MaxLines is the maximum number of lines and MaxWidth is the
+// maximum number of characters on each line. A zero value means there is no limit.
+type LabelLimits struct {
+	MaxLines int
+	MaxWidth int
+}
+
+// FormatWithLimits formats an address for a postal label that can only fit a limited number of lines and characters
+// per line, such as carrier labels. The address is formatted as plain text with `\n`s for new lines, regardless of
+// the Outputter.
+// To make the address fit, the administrative area is abbreviated using its postal key, long street address lines are
+// wrapped and street address lines are merged when there are too many lines. If the address still does not fit, an
+// ErrLabelOverflow is returned listing the fields that could not fit, rather than truncating the address.
+func (f PostalLabelFormatter) FormatWithLimits(address Address, language string, limits LabelLimits) (string, error) {
+
+	lines, err := f.layoutWithLimits(address, language, limits)
+
+	if err != nil {
+		return "", err
+	}
+
+	var result []string
+
+	for _, line := range lines {
+		result = append(result, line.String())
+	}
+
+	return strings.Join(result, "\n"), nil
+}
+
+// layoutWithLimits lays out an address for a postal label within the limits. See FormatWithLimits for details.
+func (f PostalLabelFormatter) layoutWithLimits(address Address, language string, limits LabelLimits) ([]layoutLine, error) {
+
+	format, addressData, upper := f.prepare(address, language)
+
+	lines := layoutFormat(format, addressData, upper)
+
+	lines = fitLabelWidth(lines, addressData, limits.MaxWidth)
+
+	lines = fitLabelLines(lines, limits)
+
+	overflow := ErrLabelOverflow{
+		country: address.Country,
+	}
+
+	for i, line := range lines {
+		if exceedsWidth(line.String(), limits.MaxWidth) || (limits.MaxLines > 0 && i >= limits.MaxLines) {
+			for _, field := range line.fields() {
+				if !containsField(overflow.Fields, field) {
+					overflow.Fields = append(overflow.Fields, field)
+				}
+			}
+		}
+	}
+
+	if len(overflow.Fields) > 0 {
+		return nil, overflow
+	}
+
+	return lines, nil
+}
+
+func exceedsWidth(line string, maxWidth int) bool {
+	return maxWidth > 0 && utf8.RuneCountInString(line) > maxWidth
+}
+
+// fitLabelWidth abbreviates the administrative area and wraps street address lines that are wider than the label.
+func fitLabelWidth(lines []layoutLine, addressData formatData, maxWidth int) []layoutLine {
+
+	if maxWidth <= 0 {
+		return lines
+	}
+
+	var result []layoutLine
+
+	for _, line := range lines {
+
+		if !exceedsWidth(line.String(), maxWidth) {
+			result = append(result, line)
+			continue
+		}
+
+		if addressData.AdministrativeAreaPostalKey != "" && line.hasField(AdministrativeArea) {
+
+			for i, token := range line {
+				if token.Field == AdministrativeArea && utf8.RuneCountInString(addressData.AdministrativeAreaPostalKey) < utf8.RuneCountInString(token.Value) {
+					line[i].Value = applyUpper(addressData.AdministrativeAreaPostalKey, token.Upper)
+				}
+			}
+
+			if !exceedsWidth(line.String(), maxWidth) {
+				result = append(result, line)
+				continue
+			}
+		}
+
+		if len(line) == 1 && line[0].Field == StreetAddress {
+			result = append(result, wrapStreetAddressLine(line[0], maxWidth)...)
+			continue
+		}
+
+		result = append(result, line)
+	}
+
+	return result
+}
+
+// wrapStreetAddressLine wraps a street address line on word boundaries. Words that are wider than the label are left
+// as is so that they are reported as overflowing.
+func wrapStreetAddressLine(token FormattedToken, maxWidth int) []layoutLine {
+
+	var lines []layoutLine
+
+	current := ""
+
+	for _, word := range strings.Fields(token.Value) {
+
+		if current != "" && exceedsWidth(current+" "+word, maxWidth) {
+			lines = append(lines, layoutLine{{Field: token.Field, Value: current, Upper: token.Upper}})
+			current = ""
+		}
+
+		if current == "" {
+			current = word
+		} else {
+			current += " " + word
+		}
+	}
+
+	if current != "" {
+		lines = append(lines, layoutLine{{Field: token.Field, Value: current, Upper: token.Upper}})
+	}
+
+	return lines
+}
+
+// fitLabelLines merges adjacent street address lines while the label has too many lines, as long as the merged line
+// fits the width of the label. The shortest pairs are merged first.
+func fitLabelLines(lines []layoutLine, limits LabelLimits) []layoutLine {
+
+	if limits.MaxLines <= 0 {
+		return lines
+	}
+
+	for len(lines) > limits.MaxLines {
+
+		best := -1
+		bestWidth := 0
+
+		for i := 0; i < len(lines)-1; i++ {
+
+			if !isStreetAddressLine(lines[i]) || !isStreetAddressLine(lines[i+1]) {
+				continue
+			}
+
+			merged := lines[i].String() + ", " + lines[i+1].String()
+
+			if exceedsWidth(merged, limits.MaxWidth) {
+				continue
+			}
+
+			if width := utf8.RuneCountInString(merged); best == -1 || width < bestWidth {
+				best = i
+				bestWidth = width
+			}
+		}
+
+		if best == -1 {
+			break
+		}
+
+		merged := layoutLine{{
+			Field: StreetAddress,
+			Value: lines[best].String() + ", " + lines[best+1].String(),
+			Upper: lines[best][0].Upper,
+		}}
+
+		lines = append(lines[:best], append([]layoutLine{merged}, lines[best+2:]...)...)
+	}
+
+	return lines
+}
+
+func isStreetAddressLine(line layoutLine) bool {
+	return len(line) == 1 && line[0].Field == StreetAddress
+}
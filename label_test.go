@@ -0,0 +1,222 @@
+package address
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestPostalLabelFormatterWithLimits(t *testing.T) {
+
+	testCases := []struct {
+		Formatter PostalLabelFormatter
+		Limits    LabelLimits
+		Address   []func(*Address)
+		Expected  string
+	}{
+		{
+			Formatter: PostalLabelFormatter{
+				Output:            StringOutputter{},
+				OriginCountryCode: "FR",
+			},
+			Limits: LabelLimits{
+				MaxLines: 5,
+				MaxWidth: 35,
+			},
+			Address: []func(*Address){
+				WithName("John Smith"),
+				WithOrganization("Company Pty Ltd"),
+				WithStreetAddress([]string{
+					"525 Collins Street",
+				}),
+				WithLocality("Melbourne"),
+				WithAdministrativeArea("VIC"),
+				WithPostCode("3000"),
+				WithCountry("AU"),
+			},
+			Expected: "Company Pty Ltd\nJohn Smith\n525 Collins Street\nMELBOURNE VIC 3000\nAUSTRALIE - AUSTRALIA",
+		},
+		{
+			Formatter: PostalLabelFormatter{
+				Output:            StringOutputter{},
+				OriginCountryCode: "AU",
+			},
+			Limits: LabelLimits{
+				MaxLines: 4,
+				MaxWidth: 35,
+			},
+			Address: []func(*Address){
+				WithName("John Smith"),
+				WithStreetAddress([]string{
+					"Suite 7",
+					"Level 9",
+					"525 Collins Street",
+				}),
+				WithLocality("Melbourne"),
+				WithAdministrativeArea("VIC"),
+				WithPostCode("3000"),
+				WithCountry("AU"),
+			},
+			Expected: "John Smith\nSuite 7, Level 9\n525 Collins Street\nMELBOURNE VIC 3000",
+		},
+		{
+			Formatter: PostalLabelFormatter{
+				Output:            StringOutputter{},
+				OriginCountryCode: "AU",
+			},
+			Limits: LabelLimits{
+				MaxLines: 5,
+				MaxWidth: 20,
+			},
+			Address: []func(*Address){
+				WithName("John Smith"),
+				WithStreetAddress([]string{
+					"Suite 7, 525 Collins Street",
+				}),
+				WithLocality("Melbourne"),
+				WithAdministrativeArea("VIC"),
+				WithPostCode("3000"),
+				WithCountry("AU"),
+			},
+			Expected: "John Smith\nSuite 7, 525 Collins\nStreet\nMELBOURNE VIC 3000",
+		},
+		{
+			Formatter: PostalLabelFormatter{
+				Output:            StringOutputter{},
+				OriginCountryCode: "US",
+			},
+			Limits: LabelLimits{},
+			Address: []func(*Address){
+				WithName("John Smith"),
+				WithStreetAddress([]string{
+					"1 Microsoft Way",
+				}),
+				WithAdministrativeArea("WA"),
+				WithPostCode("98052"),
+				WithCountry("US"),
+			},
+			Expected: "John Smith\n1 Microsoft Way\nWA 98052",
+		},
+		{
+			Formatter: PostalLabelFormatter{
+				Output:            StringOutputter{},
+				OriginCountryCode: "US",
+			},
+			Limits: LabelLimits{},
+			Address: []func(*Address){
+				WithName("John Smith"),
+				WithStreetAddress([]string{
+					"1 Main Street",
+				}),
+				WithLocality("Springfield"),
+				WithPostCode("12345"),
+				WithCountry("US"),
+			},
+			Expected: "John Smith\n1 Main Street\nSPRINGFIELD, 12345",
+		},
+		{
+			Formatter: PostalLabelFormatter{
+				Output:            StringOutputter{},
+				OriginCountryCode: "CH",
+			},
+			Limits: LabelLimits{},
+			Address: []func(*Address){
+				WithName("Hans Muster"),
+				WithStreetAddress([]string{
+					"Bahnhofstrasse 1",
+				}),
+				WithLocality("Zürich"),
+				WithCountry("CH"),
+			},
+			Expected: "Hans Muster\nBahnhofstrasse 1\nZÜRICH",
+		},
+	}
+
+	for i, testCase := range testCases {
+
+		address := New(testCase.Address...)
+
+		formatted, err := testCase.Formatter.FormatWithLimits(address, "", testCase.Limits)
+
+		if err != nil {
+			t.Fatalf("Unexpected error formatting test case %d: %s", i, err)
+		}
+
+		if formatted != testCase.Expected {
+			t.Errorf("Formatted address for test case %d does not match the expected result, got %q", i, formatted)
+		}
+	}
+}
+
+func TestPostalLabelFormatterWithLimitsOverflow(t *testing.T) {
+
+	f := PostalLabelFormatter{
+		Output:            StringOutputter{},
+		OriginCountryCode: "FR",
+	}
+
+	testCases := []struct {
+		Limits   LabelLimits
+		Address  []func(*Address)
+		Expected []Field
+	}{
+		{
+			Limits: LabelLimits{
+				MaxLines: 4,
+				MaxWidth: 35,
+			},
+			Address: []func(*Address){
+				WithName("John Smith"),
+				WithOrganization("Company Pty Ltd"),
+				WithStreetAddress([]string{
+					"525 Collins Street",
+				}),
+				WithLocality("Melbourne"),
+				WithAdministrativeArea("VIC"),
+				WithPostCode("3000"),
+				WithCountry("AU"),
+			},
+			Expected: []Field{Country},
+		},
+		{
+			Limits: LabelLimits{
+				MaxLines: 5,
+				MaxWidth: 12,
+			},
+			Address: []func(*Address){
+				WithName("John Smith"),
+				WithOrganization("Some Company Pty Ltd"),
+				WithStreetAddress([]string{
+					"525 Collins Street",
+				}),
+				WithLocality("Melbourne"),
+				WithAdministrativeArea("VIC"),
+				WithPostCode("3000"),
+				WithCountry("AU"),
+			},
+			Expected: []Field{Organization, Locality, AdministrativeArea, PostCode, Country},
+		},
+	}
+
+	for i, testCase := range testCases {
+
+		address := New(testCase.Address...)
+
+		_, err := f.FormatWithLimits(address, "", testCase.Limits)
+
+		var overflow ErrLabelOverflow
+
+		if !errors.As(err, &overflow) {
+			t.Fatalf("Expected an ErrLabelOverflow for test case %d, got %v", i, err)
+		}
+
+		if len(overflow.Fields) != len(testCase.Expected) {
+			t.Fatalf("Expected %d overflowing fields for test case %d, got %v", len(testCase.Expected), i, overflow.Fields)
+		}
+
+		for j, field := range testCase.Expected {
+			if overflow.Fields[j] != field {
+				t.Errorf("Expected overflowing field %d for test case %d to be %s, got %s", j, i, field, overflow.Fields[j])
+			}
+		}
+	}
+}
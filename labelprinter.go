@@ -0,0 +1,269 @@
+package address
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/encoding/charmap"
+)
+
+// ZPLOptions configures the ZPL II output for Zebra label printers. All sizes are in dots. A zero value uses the
+// default, which is a 4 x 6 inch label at 203 dpi printed using the scalable font 0 at 30 dots.
+// OriginX and OriginY set the label home position and are also used as the margins on the opposite sides of the label.
+// Font is either a single character printer font name (such as 0 or A) or the name of a font stored on the printer
+// (such as E:ARIAL.TTF).
+// SupportsRune reports whether the font can render a character. If it is nil, the font is assumed to only render
+// the Latin script.
+type ZPLOptions struct {
+	LabelWidth   int
+	LabelHeight  int
+	OriginX      int
+	OriginY      int
+	Font         string
+	FontHeight   int
+	FontWidth    int
+	LineSpacing  int
+	SupportsRune func(r rune) bool
+}
+
+func (o ZPLOptions) withDefaults() ZPLOptions {
+
+	if o.LabelWidth <= 0 {
+		o.LabelWidth = 812
+	}
+
+	if o.LabelHeight <= 0 {
+		o.LabelHeight = 1218
+	}
+
+	if o.Font == "" {
+		o.Font = "0"
+	}
+
+	if o.FontHeight <= 0 {
+		o.FontHeight = 30
+	}
+
+	if o.FontWidth <= 0 {
+		o.FontWidth = o.FontHeight
+	}
+
+	if o.LineSpacing <= 0 {
+		o.LineSpacing = o.FontHeight / 3
+	}
+
+	if o.SupportsRune == nil {
+		o.SupportsRune = isLatinRune
+	}
+
+	return o
+}
+
+// limits returns the number of lines and characters per line that fit on the label. Each character is assumed to be
+// FontWidth dots wide, which is conservative for proportional fonts.
+func (o ZPLOptions) limits() LabelLimits {
+
+	width := o.LabelWidth - 2*o.OriginX
+	height := o.LabelHeight - 2*o.OriginY
+
+	return LabelLimits{
+		MaxLines: max(1, (height+o.LineSpacing)/(o.FontHeight+o.LineSpacing)),
+		MaxWidth: max(1, width/o.FontWidth),
+	}
+}
+
+// FormatZPL formats an address for a postal label and renders it as a ZPL II label for Zebra thermal printers.
+// The address is laid out as in FormatWithLimits, using the number of lines and characters that fit on the label.
+// If the address contains characters that the font cannot render, it is formatted using the latinized format, the
+// latinized names of the subdivisions and the English name of the country instead. If that does not help, an
+// ErrUnsupportedCharacters is returned.
+// The output only depends on the address and options, so it can be compared against golden files.
+func (f PostalLabelFormatter) FormatZPL(address Address, language string, options ZPLOptions) (string, error) {
+
+	options = options.withDefaults()
+
+	lines, err := f.printableLayout(address, language, options.limits(), options.SupportsRune)
+
+	if err != nil {
+		return "", err
+	}
+
+	font := fmt.Sprintf("^A%sN,%d,%d", options.Font, options.FontHeight, options.FontWidth)
+
+	if len(options.Font) > 1 {
+		font = fmt.Sprintf("^A@N,%d,%d,%s", options.FontHeight, options.FontWidth, options.Font)
+	}
+
+	var b strings.Builder
+
+	b.WriteString("^XA\n")
+	b.WriteString("^CI28\n")
+	fmt.Fprintf(&b, "^PW%d\n", options.LabelWidth)
+	fmt.Fprintf(&b, "^LL%d\n", options.LabelHeight)
+	fmt.Fprintf(&b, "^LH%d,%d\n", options.OriginX, options.OriginY)
+
+	for i, line := range lines {
+		fmt.Fprintf(&b, "^FO0,%d%s^FH^FD%s^FS\n", i*(options.FontHeight+options.LineSpacing), font, zplEscaper.Replace(line.String()))
+	}
+
+	b.WriteString("^XZ\n")
+
+	return b.String(), nil
+}
+
+// The field data is written using ^FH, so the hexadecimal indicator and the characters that start ZPL commands are
+// escaped as hexadecimal.
+var zplEscaper = strings.NewReplacer("_", "_5F", "^", "_5E", "~", "_7E")
+
+// ESCPOSOptions configures the ESC/POS output for receipt printers. CharsPerLine is the number of characters that fit
+// on a line, which defaults to 48 (font A on 80 mm paper). MaxLines limits the number of lines, with zero meaning there
+// is no limit. If Cut is true, the paper is cut after the address.
+type ESCPOSOptions struct {
+	CharsPerLine int
+	MaxLines     int
+	Cut          bool
+}
+
+// FormatESCPOS formats an address for a postal label and renders it as ESC/POS commands for receipt printers.
+// The text is printed using the Windows-1252 code page. The address is laid out as in FormatWithLimits and, if it
+// contains characters that are not in the code page, it is formatted using the latinized format, the latinized names
+// of the subdivisions and the English name of the country instead. If that does not help, an ErrUnsupportedCharacters
+// is returned.
+// The output only depends on the address and options, so it can be compared against golden files.
+func (f PostalLabelFormatter) FormatESCPOS(address Address, language string, options ESCPOSOptions) ([]byte, error) {
+
+	if options.CharsPerLine <= 0 {
+		options.CharsPerLine = 48
+	}
+
+	limits := LabelLimits{
+		MaxLines: options.MaxLines,
+		MaxWidth: options.CharsPerLine,
+	}
+
+	lines, err := f.printableLayout(address, language, limits, isWindows1252Rune)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var b bytes.Buffer
+
+	b.Write([]byte{0x1b, 0x40})       // ESC @: initialize the printer
+	b.Write([]byte{0x1b, 0x74, 0x10}) // ESC t 16: select the Windows-1252 code page
+
+	encoder := charmap.Windows1252.NewEncoder()
+
+	for _, line := range lines {
+
+		encoded, err := encoder.String(line.String())
+
+		if err != nil {
+			return nil, ErrUnsupportedCharacters{
+				country: address.Country,
+				Fields:  line.fields(),
+			}
+		}
+
+		b.WriteString(encoded)
+		b.WriteByte('\n')
+	}
+
+	b.Write([]byte{0x1b, 0x64, 0x03}) // ESC d 3: feed 3 lines
+
+	if options.Cut {
+		b.Write([]byte{0x1d, 0x56, 0x42, 0x00}) // GS V 66 0: feed to the cutter and cut the paper
+	}
+
+	return b.Bytes(), nil
+}
+
+// printableLayout lays out an address for a label printer whose font can only render the characters accepted by
+// supportsRune, falling back to the latinized format and the English name of the country if needed. The latinized
+// format is also tried if the address does not fit on the label, and the ErrLabelOverflow is only returned if the
+// latinized address does not fit either.
+func (f PostalLabelFormatter) printableLayout(address Address, language string, limits LabelLimits, supportsRune func(r rune) bool) ([]layoutLine, error) {
+
+	lines, overflowErr := f.layoutWithLimits(address, language, limits)
+
+	if overflowErr == nil {
+		if _, unsupported := unsupportedFields(lines, supportsRune); !unsupported {
+			return lines, nil
+		}
+	}
+
+	registry := registryOrDefault(f.Registry)
+
+	latinized := f
+	latinized.Latinize = true
+
+	lines, err := latinized.layoutWithLimits(address, registry.latinizedLanguage(address.Country, language), limits)
+
+	if overflowErr != nil && err != nil {
+		return nil, overflowErr
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	// The country is named in the language of the origin country as well as English, so fall back to English only
+	for _, line := range lines {
+		for i, token := range line {
+			if token.Field == Country && strings.IndexFunc(token.Value, func(r rune) bool { return !supportsRune(r) }) != -1 {
+				line[i].Value = strings.ToUpper(registry.countryName(address.Country, "en"))
+			}
+		}
+	}
+
+	if fields, unsupported := unsupportedFields(lines, supportsRune); unsupported {
+
+		if overflowErr != nil {
+			return nil, overflowErr
+		}
+
+		return nil, ErrUnsupportedCharacters{
+			country: address.Country,
+			Fields:  fields,
+		}
+	}
+
+	return lines, nil
+}
+
+// unsupportedFields returns the fields with characters that are not accepted by supportsRune. It also reports whether
+// there are any such characters, since they can be in the literal text of the address format.
+func unsupportedFields(lines []layoutLine, supportsRune func(r rune) bool) ([]Field, bool) {
+
+	var fields []Field
+
+	unsupported := false
+
+	for _, line := range lines {
+		for _, token := range line {
+
+			if strings.IndexFunc(token.Value, func(r rune) bool { return !supportsRune(r) }) == -1 {
+				continue
+			}
+
+			unsupported = true
+
+			if token.Field != 0 && !containsField(fields, token.Field) {
+				fields = append(fields, token.Field)
+			}
+		}
+	}
+
+	return fields, unsupported
+}
+
+func isLatinRune(r rune) bool {
+	return unicode.In(r, unicode.Latin, unicode.Common, unicode.Inherited)
+}
+
+func isWindows1252Rune(r rune) bool {
+	_, ok := charmap.Windows1252.EncodeRune(r)
+	return ok
+}
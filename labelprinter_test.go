@@ -0,0 +1,151 @@
+package address
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestFormatZPL(t *testing.T) {
+
+	testCases := []struct {
+		Address  []func(*Address)
+		Language string
+		Options  ZPLOptions
+		Expected string
+	}{
+		{
+			Address: []func(*Address){
+				WithName("John_Smith"),
+				WithStreetAddress([]string{
+					"525 Collins Street",
+				}),
+				WithLocality("Melbourne"),
+				WithAdministrativeArea("VIC"),
+				WithPostCode("3000"),
+				WithCountry("AU"),
+			},
+			Language: "en",
+			Expected: "^XA\n^CI28\n^PW812\n^LL1218\n^LH0,0\n" +
+				"^FO0,0^A0N,30,30^FH^FDJohn_5FSmith^FS\n" +
+				"^FO0,40^A0N,30,30^FH^FD525 Collins Street^FS\n" +
+				"^FO0,80^A0N,30,30^FH^FDMELBOURNE VIC 3000^FS\n" +
+				"^XZ\n",
+		},
+		{
+			Address: []func(*Address){
+				WithStreetAddress([]string{
+					"1 Main Street",
+				}),
+				WithLocality("东城区"),
+				WithAdministrativeArea("11"),
+				WithPostCode("100000"),
+				WithCountry("CN"),
+			},
+			Language: "zh",
+			Options: ZPLOptions{
+				LabelWidth:  600,
+				LabelHeight: 400,
+				OriginX:     20,
+				OriginY:     20,
+				Font:        "E:ARIAL.TTF",
+				FontHeight:  24,
+			},
+			Expected: "^XA\n^CI28\n^PW600\n^LL400\n^LH20,20\n" +
+				"^FO0,0^A@N,24,24,E:ARIAL.TTF^FH^FD1 Main Street^FS\n" +
+				"^FO0,32^A@N,24,24,E:ARIAL.TTF^FH^FDDongcheng Qu^FS\n" +
+				"^FO0,64^A@N,24,24,E:ARIAL.TTF^FH^FDBEIJING SHI, 100000^FS\n" +
+				"^XZ\n",
+		},
+		{
+			Address: []func(*Address){
+				WithName("Taro Yamada"),
+				WithStreetAddress([]string{
+					"1-2-3 Marunouchi",
+				}),
+				WithAdministrativeArea("13"),
+				WithPostCode("100-0001"),
+				WithCountry("JP"),
+			},
+			Language: "ja",
+			Options: ZPLOptions{
+				LabelWidth:  600,
+				LabelHeight: 100,
+				FontHeight:  24,
+			},
+			Expected: "^XA\n^CI28\n^PW600\n^LL100\n^LH0,0\n" +
+				"^FO0,0^A0N,24,24^FH^FDTaro Yamada^FS\n" +
+				"^FO0,32^A0N,24,24^FH^FD1-2-3 Marunouchi, TOKYO^FS\n" +
+				"^FO0,64^A0N,24,24^FH^FD100-0001^FS\n" +
+				"^XZ\n",
+		},
+	}
+
+	for i, testCase := range testCases {
+
+		formatted, err := PostalLabelFormatter{}.FormatZPL(New(testCase.Address...), testCase.Language, testCase.Options)
+
+		if err != nil {
+			t.Errorf("Unexpected error formatting test case %d: %s", i, err)
+			continue
+		}
+
+		if formatted != testCase.Expected {
+			t.Errorf("ZPL output for test case %d does not match the expected result, got %q", i, formatted)
+		}
+	}
+}
+
+func TestFormatZPLUnsupportedCharacters(t *testing.T) {
+
+	address := New(
+		WithStreetAddress([]string{
+			"东城区1号",
+		}),
+		WithLocality("东城区"),
+		WithAdministrativeArea("11"),
+		WithPostCode("100000"),
+		WithCountry("CN"),
+	)
+
+	_, err := PostalLabelFormatter{}.FormatZPL(address, "zh", ZPLOptions{})
+
+	var unsupportedErr ErrUnsupportedCharacters
+
+	if !errors.As(err, &unsupportedErr) {
+		t.Fatalf("Expected ErrUnsupportedCharacters, got %v", err)
+	}
+
+	if len(unsupportedErr.Fields) != 1 || unsupportedErr.Fields[0] != StreetAddress {
+		t.Errorf("Expected the street address to be unsupported, got %v", unsupportedErr.Fields)
+	}
+
+	if _, err := (PostalLabelFormatter{}).FormatZPL(address, "zh", ZPLOptions{SupportsRune: func(r rune) bool { return true }}); err != nil {
+		t.Errorf("Unexpected error formatting with a Unicode font: %s", err)
+	}
+}
+
+func TestFormatESCPOS(t *testing.T) {
+
+	address := New(
+		WithName("François Dupont"),
+		WithStreetAddress([]string{
+			"10 rue de la Paix",
+		}),
+		WithLocality("Paris"),
+		WithPostCode("75002"),
+		WithCountry("FR"),
+	)
+
+	formatted, err := PostalLabelFormatter{OriginCountryCode: "FR"}.FormatESCPOS(address, "fr", ESCPOSOptions{Cut: true})
+
+	if err != nil {
+		t.Fatalf("Unexpected error formatting address: %s", err)
+	}
+
+	expected := []byte("\x1b@\x1bt\x10Fran\xe7ois Dupont\n10 rue de la Paix\n75002 PARIS\n\x1bd\x03\x1dVB\x00")
+
+	if !bytes.Equal(formatted, expected) {
+		t.Errorf("ESC/POS output does not match the expected result, got %q", formatted)
+	}
+}
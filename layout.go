@@ -0,0 +1,396 @@
+package address
+
+import (
+	"strings"
+	"unicode"
+)
+
+// FormattedToken is a single piece of a formatted address line. Field is the address field the value came from.
+// Literal tokens, such as separators and fixed text in the address format, have a zero Field.
+// Upper reports whether the value was converted to UPPERCASE because the field is in the country's list of fields
+// to uppercase.
+type FormattedToken struct {
+	Field Field
+	Value string
+	Upper bool
+}
+
+type layoutLine []FormattedToken
+
+func (l layoutLine) String() string {
+
+	var b strings.Builder
+
+	for _, token := range l {
+		b.WriteString(token.Value)
+	}
+
+	return strings.TrimSpace(b.String())
+}
+
+func (l layoutLine) fields() []Field {
+
+	var fields []Field
+
+	for _, token := range l {
+		if token.Field != 0 && !containsField(fields, token.Field) {
+			fields = append(fields, token.Field)
+		}
+	}
+
+	return fields
+}
+
+func (l layoutLine) hasField(field Field) bool {
+	return containsField(l.fields(), field)
+}
+
+func layoutLinesToTokens(lines []layoutLine) [][]FormattedToken {
+
+	result := make([][]FormattedToken, 0, len(lines))
+
+	for _, line := range lines {
+		result = append(result, line)
+	}
+
+	return result
+}
+
+func containsField(fields []Field, field Field) bool {
+
+	for _, f := range fields {
+		if f == field {
+			return true
+		}
+	}
+
+	return false
+}
+
+var formatTokens = map[string]Field{
+	"%country": Country,
+	"%N":       Name,
+	"%O":       Organization,
+	"%A":       StreetAddress,
+	"%D":       DependentLocality,
+	"%C":       Locality,
+	"%S":       AdministrativeArea,
+	"%Z":       PostCode,
+	"%X":       SortingCode,
+}
+
+// layoutFormat merges the address data into an address format in Google's format and returns the resulting lines.
+// Unlike the template based outputters, each piece of text in the lines keeps track of the field it came from.
+// Literals in the format that separate empty fields are dropped, as are lines that end up empty.
+func layoutFormat(format string, data formatData, upper map[Field]struct{}) []layoutLine {
+
+	var lines []layoutLine
+
+	for _, formatLine := range strings.Split(format, "%n") {
+
+		var line layoutLine
+
+		for _, token := range tokenizeFormatLine(formatLine) {
+
+			if token.Field == 0 {
+				line = append(line, token)
+				continue
+			}
+
+			_, isUpper := upper[token.Field]
+
+			if token.Field == StreetAddress {
+
+				for i, addressLine := range data.StreetAddress {
+
+					if i > 0 {
+						lines = append(lines, line)
+						line = nil
+					}
+
+					line = append(line, FormattedToken{
+						Field: StreetAddress,
+						Value: applyUpper(addressLine, isUpper),
+						Upper: isUpper,
+					})
+				}
+
+				continue
+			}
+
+			line = append(line, FormattedToken{
+				Field: token.Field,
+				Value: applyUpper(strings.TrimSpace(data.fieldValue(token.Field)), isUpper),
+				Upper: isUpper,
+			})
+		}
+
+		lines = append(lines, line)
+	}
+
+	var result []layoutLine
+
+	for _, line := range lines {
+
+		line = dropEmptyFields(line)
+
+		if len(line) > 0 {
+			result = append(result, line)
+		}
+	}
+
+	return result
+}
+
+func tokenizeFormatLine(formatLine string) []FormattedToken {
+
+	var tokens []FormattedToken
+
+	literal := ""
+
+	for i := 0; i < len(formatLine); {
+
+		matched := false
+
+		if formatLine[i] == '%' {
+			for key, field := range formatTokens {
+				if strings.HasPrefix(formatLine[i:], key) {
+
+					if literal != "" {
+						tokens = append(tokens, FormattedToken{Value: literal})
+						literal = ""
+					}
+
+					tokens = append(tokens, FormattedToken{Field: field})
+					i += len(key)
+					matched = true
+
+					break
+				}
+			}
+		}
+
+		if !matched {
+			literal += formatLine[i : i+1]
+			i++
+		}
+	}
+
+	if literal != "" {
+		tokens = append(tokens, FormattedToken{Value: literal})
+	}
+
+	return tokens
+}
+
+// dropEmptyFields removes empty fields from a line along with the literals that are only there to separate them.
+// Literals at the start of a line are treated as a prefix of the next field (for example, "〒" in Japan or "CH-" in
+// Switzerland). Literals between fields keep any text other than punctuation and whitespace when one of the fields
+// is empty (for example, " PR " in Puerto Rico). The literals around empty fields between two fields that are not
+// empty are collapsed into a single separator, so that "%C, %S %Z" without an administrative area becomes "C, Z".
+func dropEmptyFields(line layoutLine) layoutLine {
+
+	hasFields := false
+	hasValues := false
+
+	for _, token := range line {
+		if token.Field != 0 {
+			hasFields = true
+
+			if token.Value != "" {
+				hasValues = true
+			}
+		}
+	}
+
+	if !hasFields {
+		if strings.TrimSpace(line.String()) == "" {
+			return nil
+		}
+
+		return layoutLine{{Value: strings.TrimSpace(line.String())}}
+	}
+
+	if !hasValues {
+		return nil
+	}
+
+	var result layoutLine
+
+	for i, token := range line {
+
+		if token.Field != 0 {
+			if token.Value != "" {
+				result = append(result, token)
+			}
+
+			continue
+		}
+
+		previous := nonEmptyFieldBefore(line, i)
+		next := nonEmptyFieldAfter(line, i)
+
+		switch {
+		case previous && next:
+			if separator, ok := gapSeparator(line, i); ok {
+				token.Value = separator
+				result = append(result, token)
+			}
+
+		case !previous && fieldAfter(line, i) && !fieldBefore(line, i):
+			if next && line[i+1].Value != "" {
+				result = append(result, token)
+			}
+
+		default:
+			if text := trimSeparators(token.Value); text != "" {
+				token.Value = " " + text + " "
+				result = append(result, token)
+			}
+		}
+	}
+
+	if len(result) > 0 {
+		result[0].Value = strings.TrimLeftFunc(result[0].Value, unicode.IsSpace)
+		result[len(result)-1].Value = strings.TrimRightFunc(result[len(result)-1].Value, unicode.IsSpace)
+	}
+
+	return result
+}
+
+// gapSeparator returns the separator to use for the literal at i, which is between two fields that are not empty. If
+// there are only literals between the fields, the literal is kept as is. Otherwise, the literals between the fields
+// are collapsed into the first one, keeping any text other than punctuation and whitespace.
+func gapSeparator(line layoutLine, i int) (string, bool) {
+
+	start := i
+	for start > 0 && (line[start-1].Field == 0 || line[start-1].Value == "") {
+		start--
+	}
+
+	end := i
+	for end < len(line)-1 && (line[end+1].Field == 0 || line[end+1].Value == "") {
+		end++
+	}
+
+	var literals []string
+	hasEmptyFields := false
+
+	for _, token := range line[start : end+1] {
+		if token.Field != 0 {
+			hasEmptyFields = true
+		} else {
+			literals = append(literals, token.Value)
+		}
+	}
+
+	if !hasEmptyFields {
+		return line[i].Value, true
+	}
+
+	// Only the first literal between the fields is kept
+	for j := start; j < i; j++ {
+		if line[j].Field == 0 {
+			return "", false
+		}
+	}
+
+	var texts []string
+
+	for _, literal := range literals {
+		if text := trimSeparators(literal); text != "" {
+			texts = append(texts, text)
+		}
+	}
+
+	if len(texts) > 0 {
+		return " " + strings.Join(texts, " ") + " ", true
+	}
+
+	return literals[0], true
+}
+
+func nonEmptyFieldBefore(line layoutLine, i int) bool {
+
+	for j := i - 1; j >= 0; j-- {
+		if line[j].Field != 0 && line[j].Value != "" {
+			return true
+		}
+	}
+
+	return false
+}
+
+func nonEmptyFieldAfter(line layoutLine, i int) bool {
+
+	for j := i + 1; j < len(line); j++ {
+		if line[j].Field != 0 && line[j].Value != "" {
+			return true
+		}
+	}
+
+	return false
+}
+
+func fieldBefore(line layoutLine, i int) bool {
+
+	for j := i - 1; j >= 0; j-- {
+		if line[j].Field != 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+func fieldAfter(line layoutLine, i int) bool {
+
+	for j := i + 1; j < len(line); j++ {
+		if line[j].Field != 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+func trimSeparators(s string) string {
+	return strings.TrimFunc(s, func(r rune) bool {
+		return unicode.IsSpace(r) || unicode.IsPunct(r)
+	})
+}
+
+func applyUpper(value string, upper bool) string {
+
+	if upper {
+		return strings.ToUpper(value)
+	}
+
+	return value
+}
+
+func (f formatData) fieldValue(field Field) string {
+
+	switch field {
+	case Country:
+		return f.Country
+	case Name:
+		return f.Name
+	case Organization:
+		return f.Organization
+	case StreetAddress:
+		return strings.Join(f.StreetAddress, "\n")
+	case DependentLocality:
+		return f.DependentLocality
+	case Locality:
+		return f.Locality
+	case AdministrativeArea:
+		return f.AdministrativeArea
+	case PostCode:
+		return f.PostCode
+	case SortingCode:
+		return f.SortingCode
+	}
+
+	return ""
+}
@@ -0,0 +1,132 @@
+package address
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+	"strings"
+
+	"github.com/Boostport/address/internal/addressdata"
+)
+
+// LoadRegistry creates a Registry from address data in the format of Google's Address Data Service, instead of the
+// data compiled into the package. This allows the data to be updated without upgrading the package.
+//
+// Each JSON document returned by the service is stored in a file named after its ID with a .json extension, so the
+// list of countries is stored in data.json, a country in data/AU.json, a country in another language in
+// data/CA--fr.json and a subdivision in data/CN/北京市.json. All the countries listed in data.json, as well as ZZ, are
+// loaded. The file system can be a directory (using os.DirFS) or an embed.FS.
+//
+// The data is processed by the same code as the generator, including its sanity checks of the post code regular
+// expressions against the examples in the data. If a file is missing or the data fails a sanity check, an error
+// wrapping ErrInvalidAddressData is returned. Options such as WithCountryOverride are applied to the loaded data.
+func LoadRegistry(fsys fs.FS, options ...func(*Registry)) (*Registry, error) {
+	return loadRegistry(func(id string) ([]byte, error) {
+		return fs.ReadFile(fsys, id+".json")
+	}, options)
+}
+
+// LoadRegistryFromTar creates a Registry from a tar archive of address data in the format of Google's Address Data
+// Service. The archive can be compressed using gzip and must contain the files described in LoadRegistry, optionally
+// in a directory named ./ (as created by running tar in the directory containing data.json).
+func LoadRegistryFromTar(r io.Reader, options ...func(*Registry)) (*Registry, error) {
+
+	reader := bufio.NewReader(r)
+
+	if magic, err := reader.Peek(2); err == nil && magic[0] == 0x1f && magic[1] == 0x8b {
+
+		gzipReader, err := gzip.NewReader(reader)
+
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidAddressData, err)
+		}
+
+		defer gzipReader.Close()
+
+		r = gzipReader
+	} else {
+		r = reader
+	}
+
+	files := map[string][]byte{}
+
+	tarReader := tar.NewReader(r)
+
+	for {
+		header, err := tarReader.Next()
+
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidAddressData, err)
+		}
+
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		var contents bytes.Buffer
+
+		if _, err := io.Copy(&contents, tarReader); err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidAddressData, err)
+		}
+
+		files[path.Clean(strings.TrimPrefix(header.Name, "./"))] = contents.Bytes()
+	}
+
+	return loadRegistry(func(id string) ([]byte, error) {
+
+		contents, ok := files[id+".json"]
+
+		if !ok {
+			return nil, fmt.Errorf("open %s.json: %w", id, fs.ErrNotExist)
+		}
+
+		return contents, nil
+	}, options)
+}
+
+func loadRegistry(read func(id string) ([]byte, error), options []func(*Registry)) (*Registry, error) {
+
+	countryCodes, err := addressdata.CountryCodes(read)
+
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidAddressData, err)
+	}
+
+	countryCodes = append(countryCodes, "ZZ") // Include the fall back ZZ (unknown) country
+
+	loaded := map[string]country{}
+
+	for _, countryCode := range countryCodes {
+
+		c, err := addressdata.ProcessCountry(read, countryCode)
+
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidAddressData, err)
+		}
+
+		loaded[countryCode] = newCountry(c)
+	}
+
+	registry := &Registry{
+		data: data{
+			countries: loaded,
+		},
+		overrides: map[string]CountryOverride{},
+	}
+
+	for _, option := range options {
+		option(registry)
+	}
+
+	return registry, nil
+}
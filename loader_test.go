@@ -0,0 +1,529 @@
+package address
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"io/fs"
+	"os"
+	"reflect"
+	"testing"
+	"testing/fstest"
+)
+
+func TestLoadRegistry(t *testing.T) {
+
+	registry, err := LoadRegistry(os.DirFS("testdata/addressdata"))
+
+	if err != nil {
+		t.Fatalf("Unexpected error loading registry: %s", err)
+	}
+
+	testLoadedFixtures(t, registry)
+
+	address := New(
+		WithStreetAddress([]string{"525 Collins Street"}),
+		WithLocality("Melbourne"),
+		WithAdministrativeArea("VIC"),
+		WithPostCode("3000"),
+		WithCountry("AU"),
+	)
+
+	if err := registry.Validate(address); err != nil {
+		t.Errorf("Unexpected error validating address using loaded registry: %s", err)
+	}
+
+	address.Country = "NZ"
+
+	if err := registry.Validate(address); !errors.Is(err, ErrInvalidCountryCode) {
+		t.Errorf("Expected ErrInvalidCountryCode for a country that was not loaded, got %v", err)
+	}
+}
+
+func TestLoadRegistryFromTar(t *testing.T) {
+
+	var archive bytes.Buffer
+
+	gzipWriter := gzip.NewWriter(&archive)
+	tarWriter := tar.NewWriter(gzipWriter)
+
+	err := fs.WalkDir(os.DirFS("testdata/addressdata"), ".", func(path string, entry fs.DirEntry, err error) error {
+
+		if err != nil || entry.IsDir() {
+			return err
+		}
+
+		contents, err := os.ReadFile("testdata/addressdata/" + path)
+
+		if err != nil {
+			return err
+		}
+
+		err = tarWriter.WriteHeader(&tar.Header{
+			Name: "./" + path,
+			Mode: 0644,
+			Size: int64(len(contents)),
+		})
+
+		if err != nil {
+			return err
+		}
+
+		_, err = tarWriter.Write(contents)
+
+		return err
+	})
+
+	if err != nil {
+		t.Fatalf("Unexpected error creating archive: %s", err)
+	}
+
+	if err := tarWriter.Close(); err != nil {
+		t.Fatalf("Unexpected error closing tar writer: %s", err)
+	}
+
+	if err := gzipWriter.Close(); err != nil {
+		t.Fatalf("Unexpected error closing gzip writer: %s", err)
+	}
+
+	registry, err := LoadRegistryFromTar(&archive)
+
+	if err != nil {
+		t.Fatalf("Unexpected error loading registry: %s", err)
+	}
+
+	testLoadedFixtures(t, registry)
+}
+
+// TestLoadRegistrySnapshot compares a recorded snapshot of Google's Address Data Service with the compiled in data.
+// The snapshot in testdata only contains a few countries. Set ADDRESS_DATA_SNAPSHOT to the directory containing a
+// complete snapshot to compare all the countries.
+func TestLoadRegistrySnapshot(t *testing.T) {
+
+	dir := os.Getenv("ADDRESS_DATA_SNAPSHOT")
+
+	if dir == "" {
+		dir = "testdata/addressdata"
+	}
+
+	registry, err := LoadRegistry(os.DirFS(dir))
+
+	if err != nil {
+		t.Fatalf("Unexpected error loading registry: %s", err)
+	}
+
+	if os.Getenv("ADDRESS_DATA_SNAPSHOT") != "" && !reflect.DeepEqual(registry.data.countryCodes(), generated.countryCodes()) {
+		t.Errorf("Expected countries %v to be loaded, got %v", generated.countryCodes(), registry.data.countryCodes())
+	}
+
+	testLoadedData(t, registry)
+}
+
+// testLoadedFixtures checks the data loaded from the fixtures against the values in the fixtures. The compiled in data
+// is not used, as it would not detect the fixtures and the compiled in data being wrong in the same way.
+func testLoadedFixtures(t *testing.T, registry *Registry) {
+
+	t.Helper()
+
+	if loaded := registry.data.countryCodes(); !reflect.DeepEqual(loaded, []string{"AU", "CA", "PR", "ZZ"}) {
+		t.Errorf("Expected countries AU, CA, PR and ZZ to be loaded, got %v", loaded)
+	}
+
+	au := registry.GetCountry("AU")
+	ca := registry.GetCountry("CA")
+	pr := registry.GetCountry("PR")
+
+	testCases := []struct {
+		Value    any
+		Expected any
+	}{
+		{Value: au.PostCodeRegex.Regex, Expected: `^(\d{4})$`},
+		{Value: au.PostCodeRegex.Examples, Expected: []string{"2060", "3171", "6430", "4000", "4006", "3001"}},
+		{Value: au.PostCodeRegex.SubdivisionRegex["NT"].Regex, Expected: "^0[89]"},
+		{Value: au.PostCodeRegex.SubdivisionRegex["NT"].Examples, Expected: []string{"0800", "0999"}},
+		{Value: pr.PostCodeRegex.Examples, Expected: []string{"00930"}},
+		{Value: au.PostURL, Expected: "http://www1.auspost.com.au/postcodes/"},
+		{Value: ca.PostURL, Expected: "https://www.canadapost.ca/cpo/mc/personal/postalcode/fpc.jsf"},
+		{Value: pr.PostCodePrefix, Expected: "PR "},
+		{Value: au.AdministrativeAreas["en"][1], Expected: AdministrativeAreaData{ID: "NSW", Name: "New South Wales", PostalKey: "NSW", ISOID: "NSW"}},
+		{Value: ca.AdministrativeAreas["fr"][1], Expected: AdministrativeAreaData{ID: "BC", Name: "Colombie-Britannique", PostalKey: "BC", ISOID: "BC"}},
+	}
+
+	for i, testCase := range testCases {
+		if !reflect.DeepEqual(testCase.Value, testCase.Expected) {
+			t.Errorf("Expected %v for loaded fixture test case %d, got %v", testCase.Expected, i, testCase.Value)
+		}
+	}
+}
+
+// testLoadedData checks that the countries in the loaded data are in the compiled in data and match it.
+func testLoadedData(t *testing.T, registry *Registry) {
+
+	t.Helper()
+
+	for _, countryCode := range registry.data.countryCodes() {
+
+		expected, ok := generated.country(countryCode)
+
+		if !ok {
+			t.Errorf("Loaded country %s is not in the generated data", countryCode)
+			continue
+		}
+
+		loaded, _ := registry.data.country(countryCode)
+
+		if !reflect.DeepEqual(loaded, expected) {
+			t.Errorf("Loaded data for %s does not match the generated data. Expected %+v, got %+v", countryCode, expected, loaded)
+		}
+	}
+}
+
+func TestLoadRegistrySubdivisions(t *testing.T) {
+
+	fsys := fstest.MapFS{
+		"data.json":    {Data: []byte(`{"id": "data", "countries": "XA"}`)},
+		"data/ZZ.json": {Data: []byte(`{"id": "data/ZZ", "fmt": "%N%n%O%n%A%n%C", "require": "AC", "upper": "C"}`)},
+		"data/XA.json": {Data: []byte(`{
+			"id": "data/XA",
+			"key": "XA",
+			"name": "CUSTOM",
+			"lang": "zh",
+			"languages": "zh",
+			"fmt": "%Z%n%S%C%D%n%A%n%O%n%N",
+			"lfmt": "%N%n%O%n%A%n%D%n%C%n%S, %Z",
+			"require": "ACSZ",
+			"zip": "\\d{6}",
+			"zipex": "100000,200000",
+			"sub_keys": "北京市~香港",
+			"sub_lnames": "Beijing Shi~Hong Kong",
+			"sub_isoids": "11~91",
+			"sub_mores": "true~true",
+			"sub_xzips": "~999077",
+			"sub_zips": "10~",
+			"sub_zipexs": "100000~"
+		}`)},
+		"data/XA/北京市.json": {Data: []byte(`{
+			"id": "data/XA/北京市",
+			"key": "北京市",
+			"lang": "zh",
+			"sub_keys": "西城区~东城区",
+			"sub_lnames": "Xicheng Qu~Dongcheng Qu",
+			"sub_mores": "true~false",
+			"sub_zips": "1000~1001",
+			"sub_zipexs": "100032~100100"
+		}`)},
+		"data/XA/北京市/西城区.json": {Data: []byte(`{
+			"id": "data/XA/北京市/西城区",
+			"key": "西城区",
+			"lang": "zh",
+			"sub_keys": "金融街~德胜",
+			"sub_lnames": "Jinrongjie~Desheng"
+		}`)},
+	}
+
+	registry, err := LoadRegistry(fsys)
+
+	if err != nil {
+		t.Fatalf("Unexpected error loading registry: %s", err)
+	}
+
+	expected := map[string][]administrativeArea{
+		"zh": {
+			{
+				ID:            "11",
+				Name:          "北京市",
+				PostalKey:     "北京市",
+				ISOID:         "11",
+				LatinizedName: "Beijing Shi",
+				Localities: []locality{
+					{
+						ID:            "西城区",
+						Name:          "西城区",
+						LatinizedName: "Xicheng Qu",
+						DependentLocalities: []dependentLocality{
+							{ID: "金融街", Name: "金融街", LatinizedName: "Jinrongjie"},
+							{ID: "德胜", Name: "德胜", LatinizedName: "Desheng"},
+						},
+					},
+					{ID: "东城区", Name: "东城区", LatinizedName: "Dongcheng Qu"},
+				},
+			},
+		},
+		"en": {
+			{
+				ID:            "11",
+				Name:          "Beijing Shi",
+				PostalKey:     "北京市",
+				ISOID:         "11",
+				LatinizedName: "Beijing Shi",
+				Localities: []locality{
+					{ID: "东城区", Name: "Dongcheng Qu", LatinizedName: "Dongcheng Qu"},
+					{
+						ID:            "西城区",
+						Name:          "Xicheng Qu",
+						LatinizedName: "Xicheng Qu",
+						DependentLocalities: []dependentLocality{
+							{ID: "德胜", Name: "Desheng", LatinizedName: "Desheng"},
+							{ID: "金融街", Name: "Jinrongjie", LatinizedName: "Jinrongjie"},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	if !reflect.DeepEqual(registry.data.countries["XA"].AdministrativeAreas, expected) {
+		t.Errorf("Expected administrative areas %+v, got %+v", expected, registry.data.countries["XA"].AdministrativeAreas)
+	}
+
+	expectedPostCodeRegex := postCodeRegex{
+		Regex:    `^(\d{6})$`,
+		Examples: []string{"100000", "200000"},
+		SubdivisionRegex: map[string]postCodeRegex{
+			"11": {
+				Regex:    "^10",
+				Examples: []string{"100000"},
+				SubdivisionRegex: map[string]postCodeRegex{
+					"西城区": {Regex: "^1000", Examples: []string{"100032"}},
+					"东城区": {Regex: "^1001", Examples: []string{"100100"}},
+				},
+			},
+		},
+	}
+
+	if !reflect.DeepEqual(registry.data.countries["XA"].PostCodeRegex, expectedPostCodeRegex) {
+		t.Errorf("Expected post code regex %+v, got %+v", expectedPostCodeRegex, registry.data.countries["XA"].PostCodeRegex)
+	}
+}
+
+func TestLoadRegistryInvalidData(t *testing.T) {
+
+	testCases := []struct {
+		Files    fstest.MapFS
+		NotExist bool
+	}{
+		{
+			Files: fstest.MapFS{
+				"data.json":    {Data: []byte(`{"id": "data", "countries": "XA"}`)},
+				"data/ZZ.json": {Data: []byte(`{"id": "data/ZZ"}`)},
+			},
+			NotExist: true,
+		},
+		{
+			Files: fstest.MapFS{
+				"data.json":    {Data: []byte(`{"id": "data", "countries": "XA"}`)},
+				"data/ZZ.json": {Data: []byte(`{"id": "data/ZZ"}`)},
+				"data/XA.json": {Data: []byte(`{"id": "data/XA", "key": "XA", "zip": "\\d{4}", "zipex": "1234,12345"}`)},
+			},
+		},
+		{
+			Files: fstest.MapFS{
+				"data.json":    {Data: []byte(`{"id": "data", "countries": "XA"}`)},
+				"data/ZZ.json": {Data: []byte(`{"id": "data/ZZ"}`)},
+				"data/XA.json": {Data: []byte(`{"id": "data/XA", "key": "XA", "languages": "en", "sub_keys": "A~B", "sub_zips": "1~2", "sub_zipexs": "1000~1000"}`)},
+			},
+		},
+		{
+			Files: fstest.MapFS{
+				"data.json":    {Data: []byte(`{"id": "data", "countries": "XA"}`)},
+				"data/ZZ.json": {Data: []byte(`{"id": "data/ZZ"}`)},
+				"data/XA.json": {Data: []byte(`{"id": "data/XA", "key": "XA", "fmt": "%A%n%C", "lfmt": "%A%n%C %Z"}`)},
+			},
+		},
+		{
+			Files: fstest.MapFS{
+				"data.json":    {Data: []byte(`{"id": "data", "countries": "XA"}`)},
+				"data/ZZ.json": {Data: []byte(`{"id": "data/ZZ"}`)},
+				"data/XA.json": {Data: []byte(`{"id": "data/XA", "key": "XA", "state_name_type": "canton"}`)},
+			},
+		},
+		{
+			Files: fstest.MapFS{
+				"data.json": {Data: []byte(`{"id": "data", "countries": `)},
+			},
+		},
+	}
+
+	for i, testCase := range testCases {
+
+		_, err := LoadRegistry(testCase.Files)
+
+		if !errors.Is(err, ErrInvalidAddressData) {
+			t.Errorf("Expected ErrInvalidAddressData for test case %d, got %v", i, err)
+		}
+
+		if testCase.NotExist && !errors.Is(err, fs.ErrNotExist) {
+			t.Errorf("Expected fs.ErrNotExist for test case %d, got %v", i, err)
+		}
+	}
+}
+
+func TestLoadRegistryRedirects(t *testing.T) {
+
+	fsys := fstest.MapFS{
+		"data.json":    {Data: []byte(`{"id": "data", "countries": "CN~HK~US"}`)},
+		"data/ZZ.json": {Data: []byte(`{"id": "data/ZZ", "fmt": "%N%n%O%n%A%n%C", "require": "AC", "upper": "C"}`)},
+		"data/CN.json": {Data: []byte(`{
+			"id": "data/CN",
+			"key": "CN",
+			"lang": "zh",
+			"languages": "zh",
+			"fmt": "%Z%n%S%C%D%n%A%n%O%n%N",
+			"require": "ACSZ",
+			"zip": "\\d{6}",
+			"zipex": "100000",
+			"sub_keys": "北京市~香港~福冈",
+			"sub_lnames": "Beijing Shi~Hong Kong~Fukuoka",
+			"sub_isoids": "11~91~99",
+			"sub_xrequires": "~~A",
+			"sub_xzips": "~999077~"
+		}`)},
+		"data/HK.json": {Data: []byte(`{"id": "data/HK", "key": "HK", "fmt": "%S%n%C%n%A%n%O%n%N", "require": "AS"}`)},
+		"data/US.json": {Data: []byte(`{
+			"id": "data/US",
+			"key": "US",
+			"lang": "en",
+			"languages": "en",
+			"fmt": "%N%n%O%n%A%n%C, %S %Z",
+			"require": "ACSZ",
+			"sub_keys": "AL~AA~PR",
+			"sub_names": "Alabama~Armed Forces (AA)~Puerto Rico",
+			"sub_isoids": "AL~~PR"
+		}`)},
+	}
+
+	registry, err := LoadRegistry(fsys)
+
+	if err != nil {
+		t.Fatalf("Unexpected error loading registry: %s", err)
+	}
+
+	expected := []administrativeAreaRedirect{
+		{ID: "91", Name: "香港", PostalKey: "香港", LatinizedName: "Hong Kong", CountryCode: "HK"},
+	}
+
+	if redirects := registry.data.countries["CN"].AdministrativeAreaRedirects; !reflect.DeepEqual(redirects, expected) {
+		t.Errorf("Expected redirects %+v, got %+v", expected, redirects)
+	}
+
+	if adminAreas := registry.data.countries["CN"].AdministrativeAreas["zh"]; len(adminAreas) != 1 || adminAreas[0].ID != "11" {
+		t.Errorf("Expected the redirected administrative areas to be left out, got %+v", adminAreas)
+	}
+
+	if redirects := registry.data.countries["US"].AdministrativeAreaRedirects; redirects != nil {
+		t.Errorf("Expected the US territories not to be redirected, got %+v", redirects)
+	}
+
+	if adminAreas := registry.data.countries["US"].AdministrativeAreas["en"]; len(adminAreas) != 3 || adminAreas[2].ID != "PR" {
+		t.Errorf("Expected the US territories to be kept as administrative areas, got %+v", adminAreas)
+	}
+
+	sanJuan := New(
+		WithStreetAddress([]string{
+			"1 Calle Fortaleza",
+		}),
+		WithLocality("San Juan"),
+		WithAdministrativeArea("PR"),
+		WithPostCode("00930"),
+		WithCountry("US"),
+	)
+
+	if err := registry.Validate(sanJuan); err != nil {
+		t.Errorf("Unexpected error validating an address in a US territory: %s", err)
+	}
+}
+
+func TestLoadRegistryFields(t *testing.T) {
+
+	testCases := []struct {
+		Key      string
+		Expected Field
+	}{
+		{Key: "N", Expected: Name},
+		{Key: "O", Expected: Organization},
+		{Key: "A", Expected: StreetAddress},
+		{Key: "D", Expected: DependentLocality},
+		{Key: "C", Expected: Locality},
+		{Key: "S", Expected: AdministrativeArea},
+		{Key: "Z", Expected: PostCode},
+		{Key: "X", Expected: SortingCode},
+	}
+
+	for i, testCase := range testCases {
+
+		if testCase.Key != testCase.Expected.Key() {
+			t.Errorf("Expected key %s for test case %d, got %s", testCase.Expected.Key(), i, testCase.Key)
+		}
+
+		registry, err := LoadRegistry(fstest.MapFS{
+			"data.json":    {Data: []byte(`{"id": "data", "countries": "XA"}`)},
+			"data/ZZ.json": {Data: []byte(`{"id": "data/ZZ"}`)},
+			"data/XA.json": {Data: []byte(`{"id": "data/XA", "key": "XA", "fmt": "%` + testCase.Key + `", "require": "` + testCase.Key + `"}`)},
+		})
+
+		if err != nil {
+			t.Errorf("Unexpected error loading registry for test case %d: %s", i, err)
+			continue
+		}
+
+		countryData := registry.GetCountry("XA")
+
+		if !reflect.DeepEqual(countryData.Allowed, []Field{testCase.Expected}) {
+			t.Errorf("Expected allowed fields %v for test case %d, got %v", []Field{testCase.Expected}, i, countryData.Allowed)
+		}
+
+		if !reflect.DeepEqual(countryData.Required, []Field{testCase.Expected}) {
+			t.Errorf("Expected required fields %v for test case %d, got %v", []Field{testCase.Expected}, i, countryData.Required)
+		}
+	}
+}
+
+func TestLoadRegistryFieldNames(t *testing.T) {
+
+	testCases := []struct {
+		NameType string
+		Expected FieldName
+	}{
+		{NameType: "area", Expected: Area},
+		{NameType: "city", Expected: City},
+		{NameType: "county", Expected: County},
+		{NameType: "department", Expected: Department},
+		{NameType: "district", Expected: District},
+		{NameType: "do_si", Expected: DoSi},
+		{NameType: "eircode", Expected: Eircode},
+		{NameType: "emirate", Expected: Emirate},
+		{NameType: "island", Expected: Island},
+		{NameType: "neighborhood", Expected: Neighborhood},
+		{NameType: "oblast", Expected: Oblast},
+		{NameType: "pin", Expected: PINCode},
+		{NameType: "parish", Expected: Parish},
+		{NameType: "post_town", Expected: PostTown},
+		{NameType: "postal", Expected: PostalCode},
+		{NameType: "prefecture", Expected: Prefecture},
+		{NameType: "province", Expected: Province},
+		{NameType: "state", Expected: State},
+		{NameType: "suburb", Expected: Suburb},
+		{NameType: "townland", Expected: Townland},
+		{NameType: "village_township", Expected: VillageTownship},
+		{NameType: "zip", Expected: ZipCode},
+	}
+
+	for i, testCase := range testCases {
+
+		registry, err := LoadRegistry(fstest.MapFS{
+			"data.json":    {Data: []byte(`{"id": "data", "countries": "XA"}`)},
+			"data/ZZ.json": {Data: []byte(`{"id": "data/ZZ"}`)},
+			"data/XA.json": {Data: []byte(`{"id": "data/XA", "key": "XA", "state_name_type": "` + testCase.NameType + `"}`)},
+		})
+
+		if err != nil {
+			t.Errorf("Unexpected error loading registry for test case %d: %s", i, err)
+			continue
+		}
+
+		if nameType := registry.GetCountry("XA").AdministrativeAreaNameType; nameType != testCase.Expected {
+			t.Errorf("Expected name type %s for test case %d, got %s", testCase.Expected, i, nameType)
+		}
+	}
+}
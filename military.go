@@ -0,0 +1,156 @@
+package address
+
+import (
+	"regexp"
+	"strings"
+)
+
+// militaryRegions contains the regions used by US military addresses instead of a state, along with the ZIP codes
+// assigned to them: Armed Forces Americas (AA), Europe (AE) and Pacific (AP).
+var militaryRegions = map[string]*regexp.Regexp{
+	"AA": regexp.MustCompile(`^340`),
+	"AE": regexp.MustCompile(`^09[0-8]`),
+	"AP": regexp.MustCompile(`^96[2-6]`),
+}
+
+// militaryPostOffices contains the post offices used by US military addresses instead of a city: Army/Air Post Office
+// (APO), Fleet Post Office (FPO) and Diplomatic Post Office (DPO).
+var militaryPostOffices = map[string]struct{}{
+	"APO": {},
+	"FPO": {},
+	"DPO": {},
+}
+
+// militaryFormat is the format of military addresses required by the USPS, which does not have a comma between the post
+// office and the region.
+const militaryFormat = "%N%n%O%n%A%n%C %S %Z"
+
+// militaryPunctuationRegex matches the punctuation that the USPS requires to be left out of military addresses.
+var militaryPunctuationRegex = regexp.MustCompile(`[^\p{L}\p{N}\s#/-]`)
+
+// IsMilitary reports whether an address is a US military address, which is an address in the AA, AE or AP regions or
+// at an APO, FPO or DPO post office. Military addresses are delivered by the USPS through the military postal
+// service, so they should not be offered international or commercial carrier shipping.
+func IsMilitary(address Address) bool {
+
+	if !strings.EqualFold(strings.TrimSpace(address.Country), "US") {
+		return false
+	}
+
+	_, isRegion := militaryRegions[normalizeMilitaryValue(address.AdministrativeArea)]
+	_, isPostOffice := militaryPostOffices[normalizeMilitaryValue(address.Locality)]
+
+	return isRegion || isPostOffice
+}
+
+// checkMilitaryAddress checks the rules for US military addresses, which are not in Google's data: the locality must be
+// APO, FPO or DPO, these post offices can only be used in the AA, AE and AP regions and the ZIP code must be in the
+// range of the region.
+func checkMilitaryAddress(address Address) []error {
+
+	if !IsMilitary(address) {
+		return nil
+	}
+
+	var errs []error
+
+	region := normalizeMilitaryValue(address.AdministrativeArea)
+	postCodeRegex, isRegion := militaryRegions[region]
+	_, isPostOffice := militaryPostOffices[normalizeMilitaryValue(address.Locality)]
+
+	if isRegion && !isPostOffice {
+		errs = append(errs, ErrInvalidLocality)
+	}
+
+	if isPostOffice && !isRegion && region != "" {
+		errs = append(errs, ErrInvalidAdministrativeArea)
+	}
+
+	if isRegion && address.PostCode != "" && !postCodeRegex.MatchString(strings.TrimSpace(address.PostCode)) {
+		errs = append(errs, ErrInvalidPostCode)
+	}
+
+	return errs
+}
+
+func normalizeMilitaryValue(value string) string {
+	return strings.ToUpper(strings.TrimSpace(value))
+}
+
+// MilitaryFormatter formats US military addresses in the form required by the USPS. The address is uppercased and
+// punctuation is left out, the last line contains the post office, the region and the ZIP code (for example,
+// APO AP 96278-2050) and the country is never included, as the mail is routed through the US even when it is sent
+// from another country. Addresses that are not military addresses are formatted using the PostalLabelFormatter with
+// the US as the origin country. See Formatter for the Registry field.
+type MilitaryFormatter struct {
+	Output   Outputter
+	Registry *Registry
+}
+
+// Format formats an address. The language is only used for addresses that are not military addresses. See Formatter
+// for how template errors are handled.
+func (m MilitaryFormatter) Format(address Address, language string) string {
+
+	if !IsMilitary(address) {
+		return m.postalLabelFormatter().Format(address, language)
+	}
+
+	addressData, upper := m.prepare(address)
+
+	return mustFormatTemplate(m.Output, militaryFormat, upper, addressData)
+}
+
+// FormatE formats an address in the same way as Format, but returns an error instead of panicking if the Outputter
+// produces an invalid template or the template fails to execute. The returned error wraps ErrInvalidTemplate.
+func (m MilitaryFormatter) FormatE(address Address, language string) (string, error) {
+
+	if !IsMilitary(address) {
+		return m.postalLabelFormatter().FormatE(address, language)
+	}
+
+	addressData, upper := m.prepare(address)
+
+	return formatTemplate(m.Output, militaryFormat, upper, addressData)
+}
+
+// postalLabelFormatter returns the formatter used for addresses that are not military addresses.
+func (m MilitaryFormatter) postalLabelFormatter() PostalLabelFormatter {
+	return PostalLabelFormatter{
+		Output:            m.Output,
+		OriginCountryCode: "US",
+		Registry:          m.Registry,
+	}
+}
+
+// prepare returns the data to merge into the military address format and the fields to uppercase.
+func (m MilitaryFormatter) prepare(address Address) (formatData, map[Field]struct{}) {
+
+	registry := registryOrDefault(m.Registry)
+
+	addressData := address.toFormatData(registry, registry.getCountry("US"), "en")
+
+	addressData.Name = removeMilitaryPunctuation(addressData.Name)
+	addressData.Organization = removeMilitaryPunctuation(addressData.Organization)
+
+	for i, line := range addressData.StreetAddress {
+		addressData.StreetAddress[i] = removeMilitaryPunctuation(line)
+	}
+
+	addressData.Locality = normalizeMilitaryValue(address.Locality)
+	addressData.AdministrativeArea = normalizeMilitaryValue(address.AdministrativeArea)
+	addressData.PostCode = strings.TrimSpace(address.PostCode)
+
+	upper := map[Field]struct{}{
+		Name:          {},
+		Organization:  {},
+		StreetAddress: {},
+	}
+
+	return addressData, upper
+}
+
+// removeMilitaryPunctuation removes punctuation such as periods and commas, keeping the characters used in unit and
+// box numbers.
+func removeMilitaryPunctuation(value string) string {
+	return strings.Join(strings.Fields(militaryPunctuationRegex.ReplaceAllString(value, "")), " ")
+}
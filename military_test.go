@@ -0,0 +1,206 @@
+package address
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestIsMilitary(t *testing.T) {
+
+	testCases := []struct {
+		Address  Address
+		Expected bool
+	}{
+		{
+			Address:  Address{Country: "US", AdministrativeArea: "AP", Locality: "APO"},
+			Expected: true,
+		},
+		{
+			Address:  Address{Country: "us", AdministrativeArea: " ae ", Locality: "fpo"},
+			Expected: true,
+		},
+		{
+			Address:  Address{Country: "US", AdministrativeArea: "AA", Locality: "Miami"},
+			Expected: true,
+		},
+		{
+			Address:  Address{Country: "US", AdministrativeArea: "NY", Locality: "DPO"},
+			Expected: true,
+		},
+		{
+			Address:  Address{Country: "US", AdministrativeArea: "NY", Locality: "New York"},
+			Expected: false,
+		},
+		{
+			Address:  Address{Country: "DE", Locality: "APO"},
+			Expected: false,
+		},
+	}
+
+	for i, testCase := range testCases {
+
+		if isMilitary := IsMilitary(testCase.Address); isMilitary != testCase.Expected {
+			t.Errorf("IsMilitary for test case %d returned %t, expected %t", i, isMilitary, testCase.Expected)
+		}
+	}
+}
+
+func TestValidateMilitaryAddresses(t *testing.T) {
+
+	testCases := []struct {
+		Address  Address
+		Expected []error
+	}{
+		{
+			Address: Address{
+				StreetAddress:      []string{"Unit 2050 Box 4190"},
+				Locality:           "APO",
+				AdministrativeArea: "AP",
+				PostCode:           "96278-2050",
+				Country:            "US",
+			},
+		},
+		{
+			Address: Address{
+				StreetAddress:      []string{"PSC 1234 Box 12345"},
+				Locality:           "apo",
+				AdministrativeArea: "AE",
+				PostCode:           "09204-1234",
+				Country:            "US",
+			},
+		},
+		{
+			Address: Address{
+				StreetAddress:      []string{"USNS Comfort"},
+				Locality:           "FPO",
+				AdministrativeArea: "AA",
+				PostCode:           "34055",
+				Country:            "US",
+			},
+		},
+		{
+			Address: Address{
+				StreetAddress:      []string{"Unit 8900 Box 4190"},
+				Locality:           "DPO",
+				AdministrativeArea: "AE",
+				PostCode:           "09831-4190",
+				Country:            "US",
+			},
+		},
+		{
+			Address: Address{
+				StreetAddress:      []string{"Unit 2050 Box 4190"},
+				Locality:           "Honolulu",
+				AdministrativeArea: "AP",
+				PostCode:           "96278",
+				Country:            "US",
+			},
+			Expected: []error{ErrInvalidLocality},
+		},
+		{
+			Address: Address{
+				StreetAddress:      []string{"Unit 2050 Box 4190"},
+				Locality:           "APO",
+				AdministrativeArea: "AP",
+				PostCode:           "09204",
+				Country:            "US",
+			},
+			Expected: []error{ErrInvalidPostCode},
+		},
+		{
+			Address: Address{
+				StreetAddress:      []string{"PSC 1234 Box 12345"},
+				Locality:           "APO",
+				AdministrativeArea: "AA",
+				PostCode:           "96278",
+				Country:            "US",
+			},
+			Expected: []error{ErrInvalidPostCode},
+		},
+		{
+			Address: Address{
+				StreetAddress:      []string{"PSC 1234 Box 12345"},
+				Locality:           "APO",
+				AdministrativeArea: "NY",
+				PostCode:           "10001",
+				Country:            "US",
+			},
+			Expected: []error{ErrInvalidAdministrativeArea},
+		},
+	}
+
+	for i, testCase := range testCases {
+
+		err := Validate(testCase.Address)
+
+		if len(testCase.Expected) == 0 && err != nil {
+			t.Errorf("Unexpected error validating test case %d: %s", i, err)
+		}
+
+		for _, expected := range testCase.Expected {
+
+			if !errors.Is(err, expected) {
+				t.Errorf("Expected error %q validating test case %d, got: %v", expected, i, err)
+			}
+		}
+	}
+}
+
+func TestMilitaryFormatter(t *testing.T) {
+
+	f := MilitaryFormatter{
+		Output: StringOutputter{},
+	}
+
+	testCases := []struct {
+		Address  Address
+		Expected string
+	}{
+		{
+			Address: Address{
+				Name:               "SGT. John A. Smith",
+				StreetAddress:      []string{"Unit 2050, Box 4190"},
+				Locality:           "apo",
+				AdministrativeArea: "ap",
+				PostCode:           "96278-2050",
+				Country:            "US",
+			},
+			Expected: "SGT JOHN A SMITH\nUNIT 2050 BOX 4190\nAPO AP 96278-2050",
+		},
+		{
+			Address: Address{
+				Name:               "Seaman Jane Doe",
+				StreetAddress:      []string{"USS Nimitz (CVN-68)"},
+				Locality:           "FPO",
+				AdministrativeArea: "AP",
+				PostCode:           "96620-2820",
+				Country:            "US",
+			},
+			Expected: "SEAMAN JANE DOE\nUSS NIMITZ CVN-68\nFPO AP 96620-2820",
+		},
+		{
+			Address: Address{
+				Name:               "John Smith",
+				StreetAddress:      []string{"1600 Amphitheatre Parkway"},
+				Locality:           "Mountain View",
+				AdministrativeArea: "CA",
+				PostCode:           "94043",
+				Country:            "US",
+			},
+			Expected: "John Smith\n1600 Amphitheatre Parkway\nMOUNTAIN VIEW, CA 94043",
+		},
+	}
+
+	for i, testCase := range testCases {
+
+		formatted, err := f.FormatE(testCase.Address, "")
+
+		if err != nil {
+			t.Fatalf("Error formatting test case %d: %s", i, err)
+		}
+
+		if formatted != testCase.Expected {
+			t.Errorf("Formatted address for test case %d does not match the expected result, got %q", i, formatted)
+		}
+	}
+}
@@ -0,0 +1,317 @@
+package address
+
+import (
+	"fmt"
+	"strings"
+
+	"golang.org/x/text/collate"
+	textLanguage "golang.org/x/text/language"
+	"golang.org/x/text/language/display"
+)
+
+// Registry holds the address data used to validate and format addresses, along with any per-country overrides.
+// Registries are useful for adding custom territories, patching the upstream data and using different datasets side by
+// side. The package-level functions and formatters without a Registry use the default registry, which contains the
+// data generated from Google's Address Data Service without any overrides. A Registry must not be modified after it
+// is created, so it is safe for concurrent use.
+type Registry struct {
+	data      data
+	excluded  map[string]struct{}
+	overrides map[string]CountryOverride
+}
+
+// CountryOverride overrides parts of a country's address data. The Format and LatinizedFormat use the same syntax as
+// Google's address formats (for example, `%N%n%O%n%A%n%C %S %Z`) and replace the country's formats when they are not
+// empty. The Upper, Required and Allowed fields replace the country's fields when they are not nil. To remove all
+// the fields, use an empty slice.
+type CountryOverride struct {
+	Format          string
+	LatinizedFormat string
+	Upper           []Field
+	Required        []Field
+	Allowed         []Field
+}
+
+var defaultRegistry = &Registry{
+	data:     generated,
+	excluded: excludedCountries,
+}
+
+// NewRegistry creates a Registry using the data generated from Google's Address Data Service. The registry can be
+// customized using options such as WithCountryOverride.
+func NewRegistry(options ...func(*Registry)) *Registry {
+
+	registry := &Registry{
+		data:      generated,
+		excluded:  excludedCountries,
+		overrides: map[string]CountryOverride{},
+	}
+
+	for _, option := range options {
+		option(registry)
+	}
+
+	return registry
+}
+
+// WithCountryOverride overrides the address format and fields of a country. The country code must be an ISO 3166-1
+// country code. Calling it multiple times for the same country replaces the previous override.
+func WithCountryOverride(countryCode string, override CountryOverride) func(*Registry) {
+	return func(r *Registry) {
+		r.overrides[strings.ToUpper(countryCode)] = override
+	}
+}
+
+// WithCountryData adds a country to the registry, or replaces the data of an existing country. This can be used to add
+// custom territories (such as internal warehouse "countries") or to patch the upstream data. Custom territories
+// should use codes reserved for private use that are not already in the data, such as XA to XJ and XL to XZ (XK is
+// used for Kosovo). The name is used when the country does not have a display name in the language used for
+// formatting. If the name is empty and the country already exists, its existing name is kept, as are the postal keys
+// of administrative areas that do not have one. CountryData does not contain the fields to uppercase, so an existing
+// country keeps its fields to uppercase. Use WithCountryOverride to change them.
+func WithCountryData(countryCode, name string, countryData CountryData) func(*Registry) {
+	return func(r *Registry) {
+
+		countryCode := strings.ToUpper(countryCode)
+
+		name := name
+
+		if name == "" {
+			name = r.data.name(countryCode)
+		}
+
+		internal := countryDataToInternalCountryData(countryCode, name, countryData)
+
+		existingData, _ := r.data.country(countryCode)
+
+		// Keep the existing postal keys of administrative areas created without one
+		for lang, adminAreas := range internal.AdministrativeAreas {
+			for i, adminArea := range adminAreas {
+
+				if adminArea.PostalKey != "" {
+					continue
+				}
+
+				for _, existing := range existingData.AdministrativeAreas[lang] {
+					if existing.ID == adminArea.ID {
+						adminAreas[i].PostalKey = existing.PostalKey
+					}
+				}
+			}
+		}
+
+		internal.Upper = existingData.Upper
+
+		// Copy the data so that the data of other registries is not modified
+		r.data = r.data.withCountry(countryCode, internal)
+	}
+}
+
+// Validate checks an address to determine if it is valid, using the registry's data and overrides.
+func (r *Registry) Validate(address Address) error {
+	return validate(r, address)
+}
+
+// NormalizeCountry rewrites an address whose administrative area is also a separate country to use the country code
+// of that country, using the registry's data. See NormalizeCountry for details.
+func (r *Registry) NormalizeCountry(address Address) Address {
+	return normalizeCountry(r, address)
+}
+
+// NewValid creates a new Address and validates it using the registry's data and overrides. If the address is
+// invalid, an error is returned.
+func (r *Registry) NewValid(fields ...func(*Address)) (Address, error) {
+
+	address := New(fields...)
+
+	err := r.Validate(address)
+
+	if err != nil {
+		return address, fmt.Errorf("invalid address: %w", err)
+	}
+
+	return address, nil
+}
+
+// GetCountry returns address information for a given country, including any overrides.
+func (r *Registry) GetCountry(countryCode string) CountryData {
+	return internalCountryDataToCountryData(r.getCountry(countryCode))
+}
+
+// ListCountries returns a list of countries in the registry that can be used to create addresses.
+// Language must be a valid ISO 639-1 language code such as: en, jp, zh, etc.
+// If the language does not have any translations or is invalid, then English is used as the fallback language.
+// The returned list of countries is sorted according to the chosen language.
+func (r *Registry) ListCountries(language string) []CountryListItem {
+
+	l, err := textLanguage.Parse(language)
+
+	if err != nil {
+		l = textLanguage.English
+	}
+
+	c := collate.New(l)
+
+	var countries CountryList
+
+	for _, countryCode := range r.data.countryCodes() {
+
+		if countryCode == "ZZ" {
+			continue
+		}
+
+		countries = append(countries, CountryListItem{
+			Code: countryCode,
+			Name: r.countryName(countryCode, l.String()),
+		})
+	}
+
+	c.Sort(countries)
+
+	return countries
+}
+
+// Format formats an address as a string using the DefaultFormatter and the registry's data and overrides.
+// See DefaultFormatter.Format for details.
+func (r *Registry) Format(address Address, language string) string {
+	return DefaultFormatter{
+		Output:   StringOutputter{},
+		Registry: r,
+	}.Format(address, language)
+}
+
+// FormatPostalLabel formats an address as a string for a postal label using the PostalLabelFormatter and the
+// registry's data and overrides. See PostalLabelFormatter.Format for details.
+func (r *Registry) FormatPostalLabel(address Address, language, originCountryCode string) string {
+	return PostalLabelFormatter{
+		Output:            StringOutputter{},
+		OriginCountryCode: originCountryCode,
+		Registry:          r,
+	}.Format(address, language)
+}
+
+func (r *Registry) getCountry(countryCode string) country {
+
+	data := r.data.getCountry(countryCode)
+
+	override, ok := r.overrides[countryCode]
+
+	if !ok {
+		return data
+	}
+
+	if override.Format != "" {
+		data.Format = override.Format
+	}
+
+	if override.LatinizedFormat != "" {
+		data.LatinizedFormat = override.LatinizedFormat
+	}
+
+	if override.Upper != nil {
+		data.Upper = fieldSet(override.Upper)
+	}
+
+	if override.Required != nil {
+		data.RequiredFields = fieldSet(override.Required)
+	}
+
+	if override.Allowed != nil {
+		data.AllowedFields = fieldSet(override.Allowed)
+	}
+
+	return data
+}
+
+func (r *Registry) hasCountry(countryCode string) bool {
+	return r.data.hasCountry(countryCode)
+}
+
+// checkCountry returns ErrCountryNotCompiledIn if the country was left out of the generated data and
+// ErrInvalidCountryCode if the registry does not have the country for any other reason.
+func (r *Registry) checkCountry(countryCode string) error {
+
+	if r.hasCountry(countryCode) {
+		return nil
+	}
+
+	if _, ok := r.excluded[countryCode]; ok {
+		return ErrCountryNotCompiledIn
+	}
+
+	return ErrInvalidCountryCode
+}
+
+// countryName returns the name of a country in the given language, falling back to English. If the country does not
+// have a display name (for example, custom territories), the name in the registry's data is used.
+func (r *Registry) countryName(countryCode, language string) string {
+
+	region, err := textLanguage.ParseRegion(countryCode)
+
+	if err != nil {
+		return r.data.name(countryCode)
+	}
+
+	namer := display.Regions(textLanguage.English)
+
+	if tag, err := textLanguage.Parse(language); err == nil {
+		if n := display.Regions(tag); n != nil {
+			namer = n
+		}
+	}
+
+	if name := namer.Name(region); name != "" {
+		return name
+	}
+
+	return r.data.name(countryCode)
+}
+
+func (r *Registry) normalizeLanguage(countryCode, language string) string {
+	return r.data.normalizeLanguage(countryCode, language)
+}
+
+func (r *Registry) latinizedLanguage(countryCode, language string) string {
+	return r.data.latinizedLanguage(countryCode, language)
+}
+
+// getFormat returns the address format for a country and whether it is in a latinized (minor-to-major) order.
+func (r *Registry) getFormat(countryCode string, latinized bool) (string, bool) {
+
+	countryData := r.getCountry(countryCode)
+
+	if latinized && countryData.LatinizedFormat != "" {
+		return countryData.LatinizedFormat, true
+	}
+
+	if countryData.Format != "" && countryData.LatinizedFormat == "" {
+		return countryData.Format, true
+	}
+
+	if countryData.Format != "" {
+		return countryData.Format, false
+	}
+
+	return r.getCountry("ZZ").Format, true
+}
+
+func fieldSet(fields []Field) map[Field]struct{} {
+
+	set := make(map[Field]struct{}, len(fields))
+
+	for _, field := range fields {
+		set[field] = struct{}{}
+	}
+
+	return set
+}
+
+// registryOrDefault returns the registry, or the default registry if it is nil.
+func registryOrDefault(r *Registry) *Registry {
+
+	if r == nil {
+		return defaultRegistry
+	}
+
+	return r
+}
@@ -0,0 +1,483 @@
+package address
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestRegistryCountryOverride(t *testing.T) {
+
+	registry := NewRegistry(
+		WithCountryOverride("AU", CountryOverride{
+			Format:   "%N%n%A%n%C %S%n%Z",
+			Upper:    []Field{},
+			Required: []Field{Name, StreetAddress, Locality, AdministrativeArea, PostCode},
+			Allowed:  []Field{Name, StreetAddress, Locality, AdministrativeArea, PostCode},
+		}),
+	)
+
+	address := New(
+		WithName("John Smith"),
+		WithOrganization("Company Pty Ltd"),
+		WithStreetAddress([]string{
+			"525 Collins Street",
+		}),
+		WithLocality("Melbourne"),
+		WithAdministrativeArea("VIC"),
+		WithPostCode("3000"),
+		WithCountry("AU"),
+	)
+
+	testCases := []struct {
+		Formatter Formatter
+		Expected  string
+	}{
+		{
+			Formatter: DefaultFormatter{
+				Output:   StringOutputter{},
+				Registry: registry,
+			},
+			Expected: "John Smith\n525 Collins Street\nMelbourne Victoria\n3000\nAustralia",
+		},
+		{
+			Formatter: PostalLabelFormatter{
+				Output:            StringOutputter{},
+				OriginCountryCode: "AU",
+				Registry:          registry,
+			},
+			Expected: "John Smith\n525 Collins Street\nMelbourne VIC\n3000",
+		},
+		{
+			Formatter: SingleLineFormatter{
+				Registry: registry,
+			},
+			Expected: "John Smith, 525 Collins Street, Melbourne Victoria, 3000, Australia",
+		},
+		{
+			Formatter: DefaultFormatter{
+				Output: StringOutputter{},
+			},
+			Expected: "Company Pty Ltd\nJohn Smith\n525 Collins Street\nMelbourne Victoria 3000\nAustralia",
+		},
+	}
+
+	for i, testCase := range testCases {
+		if formatted := testCase.Formatter.Format(address, "en"); formatted != testCase.Expected {
+			t.Errorf("Formatted address for test case %d does not match the expected result, got %q", i, formatted)
+		}
+	}
+
+	err := registry.Validate(address)
+
+	var unsupportedErr ErrUnsupportedFields
+
+	if !errors.As(err, &unsupportedErr) || len(unsupportedErr.Fields) != 1 || unsupportedErr.Fields[0] != Organization {
+		t.Errorf("Expected the organization to be unsupported, got %v", err)
+	}
+
+	address.Organization = ""
+	address.Name = ""
+
+	err = registry.Validate(address)
+
+	var missingErr ErrMissingRequiredFields
+
+	if !errors.As(err, &missingErr) || len(missingErr.Fields) != 1 || missingErr.Fields[0] != Name {
+		t.Errorf("Expected the name to be missing, got %v", err)
+	}
+
+	if err := Validate(address); err != nil {
+		t.Errorf("Expected the default registry to be unaffected by overrides, got %v", err)
+	}
+}
+
+func TestRegistryCustomCountryData(t *testing.T) {
+
+	warehouse := CountryData{
+		Format:          "%N%n%O%n%A%n%C",
+		Required:        []Field{Locality, StreetAddress},
+		Allowed:         []Field{Locality, Name, Organization, StreetAddress},
+		DefaultLanguage: "en",
+		AdministrativeAreas: map[string][]AdministrativeAreaData{
+			"en": {},
+		},
+	}
+
+	australia := GetCountry("AU")
+	australia.PostCodeRegex = PostCodeRegexData{
+		Regex: `^(\d{4}|MAIL)$`,
+	}
+
+	registry := NewRegistry(
+		WithCountryData("XW", "Central Warehouse", warehouse),
+		WithCountryData("AU", "", australia),
+	)
+
+	address := New(
+		WithName("Receiving"),
+		WithStreetAddress([]string{
+			"Dock 4",
+		}),
+		WithLocality("Building B"),
+		WithCountry("XW"),
+	)
+
+	if err := registry.Validate(address); err != nil {
+		t.Errorf("Unexpected error validating custom territory: %s", err)
+	}
+
+	if err := Validate(address); !errors.Is(err, ErrInvalidCountryCode) {
+		t.Errorf("Expected the custom territory to be invalid in the default registry, got %v", err)
+	}
+
+	if formatted := registry.Format(address, "en"); formatted != "Receiving\nDock 4\nBuilding B\nCentral Warehouse" {
+		t.Errorf("Formatted custom territory does not match the expected result, got %q", formatted)
+	}
+
+	found := false
+
+	for _, country := range registry.ListCountries("en") {
+		if country.Code == "XW" && country.Name == "Central Warehouse" {
+			found = true
+		}
+	}
+
+	if !found {
+		t.Errorf("Expected the custom territory to be listed")
+	}
+
+	if len(ListCountries("en")) != len(registry.ListCountries("en"))-1 {
+		t.Errorf("Expected the default registry to be unaffected by custom territories")
+	}
+
+	patched := New(
+		WithStreetAddress([]string{
+			"525 Collins Street",
+		}),
+		WithLocality("Melbourne"),
+		WithAdministrativeArea("VIC"),
+		WithPostCode("MAIL"),
+		WithCountry("AU"),
+	)
+
+	if err := registry.Validate(patched); err != nil {
+		t.Errorf("Unexpected error validating address with patched data: %s", err)
+	}
+
+	if err := Validate(patched); !errors.Is(err, ErrInvalidPostCode) {
+		t.Errorf("Expected the post code to be invalid in the default registry, got %v", err)
+	}
+
+	if formatted := registry.FormatPostalLabel(patched, "en", "US"); formatted != "525 Collins Street\nMELBOURNE VIC MAIL\nAUSTRALIA" {
+		t.Errorf("Formatted postal label with patched data does not match the expected result, got %q", formatted)
+	}
+
+	patchedData := registry.GetCountry("AU")
+
+	renamed := GetCountry("AU")
+	renamed.AdministrativeAreas["en"][0].PostalKey = "A.C.T."
+	renamed.AdministrativeAreas["en"][1].PostalKey = ""
+
+	renamedRegistry := NewRegistry(WithCountryData("AU", "", renamed))
+
+	if postalKey := renamedRegistry.data.getAdministrativeAreaPostalKey("AU", "ACT"); postalKey != "A.C.T." {
+		t.Errorf("Expected the postal key passed to WithCountryData to be used, got %q", postalKey)
+	}
+
+	if postalKey := renamedRegistry.data.getAdministrativeAreaPostalKey("AU", "NSW"); postalKey != "NSW" {
+		t.Errorf("Expected the existing postal key to be kept when none is passed, got %q", postalKey)
+	}
+
+	if patchedData.PostURL != australia.PostURL || !reflect.DeepEqual(patchedData.AdministrativeAreas, australia.AdministrativeAreas) {
+		t.Errorf("Expected the metadata of the patched country to be kept, got %+v", patchedData)
+	}
+
+	// Options can be reused, so the name kept from the first registry must not carry over to the second
+	keepName := WithCountryData("xw", "", warehouse)
+
+	for i, name := range []string{"Warehouse One", "Warehouse Two"} {
+
+		expected := NewRegistry(WithCountryData("XW", name, warehouse)).data.name("XW")
+
+		reused := NewRegistry(WithCountryData("XW", name, warehouse), keepName)
+
+		if reusedName := reused.data.name("XW"); reusedName != expected {
+			t.Errorf("Expected the name of test case %d to be %q, got %q", i, expected, reusedName)
+		}
+	}
+}
+
+func TestRegistryCountryDataRoundTrip(t *testing.T) {
+
+	testCases := []Address{
+		New(
+			WithName("Prime Minister"),
+			WithStreetAddress([]string{
+				"10 Downing Street",
+			}),
+			WithLocality("London"),
+			WithPostCode("sw1a 2aa"),
+			WithCountry("GB"),
+		),
+		New(
+			WithName("John Smith"),
+			WithStreetAddress([]string{
+				"1 Microsoft Way",
+			}),
+			WithLocality("Redmond"),
+			WithAdministrativeArea("WA"),
+			WithPostCode("98052"),
+			WithCountry("US"),
+		),
+		New(
+			WithName("John Smith"),
+			WithStreetAddress([]string{
+				"525 Collins Street",
+			}),
+			WithLocality("Melbourne"),
+			WithAdministrativeArea("VIC"),
+			WithPostCode("3000"),
+			WithCountry("AU"),
+		),
+	}
+
+	for i, address := range testCases {
+
+		registry := NewRegistry(WithCountryData(address.Country, "", GetCountry(address.Country)))
+
+		for _, origin := range []string{address.Country, "FR"} {
+
+			expected := PostalLabelFormatter{Output: StringOutputter{}, OriginCountryCode: origin}.Format(address, "")
+			formatted := PostalLabelFormatter{Output: StringOutputter{}, OriginCountryCode: origin, Registry: registry}.Format(address, "")
+
+			if formatted != expected {
+				t.Errorf("Formatted address for test case %d from %s changed after passing the country data back in, got %q, expected %q", i, origin, formatted, expected)
+			}
+		}
+
+		if !reflect.DeepEqual(registry.GetCountry(address.Country), GetCountry(address.Country)) {
+			t.Errorf("Country data for test case %d changed after passing it back in", i)
+		}
+	}
+}
+
+func TestRegistryCountryNotCompiledIn(t *testing.T) {
+
+	countries := map[string]country{}
+
+	for _, countryCode := range generated.countryCodes() {
+		if countryCode != "NZ" {
+			countries[countryCode], _ = generated.country(countryCode)
+		}
+	}
+
+	registry := &Registry{
+		data: data{
+			countries: countries,
+		},
+		excluded: map[string]struct{}{
+			"NZ": {},
+		},
+	}
+
+	testCases := []struct {
+		Country        string
+		NotCompiledIn  bool
+		InvalidCountry bool
+	}{
+		{
+			Country: "AU",
+		},
+		{
+			Country:        "NZ",
+			NotCompiledIn:  true,
+			InvalidCountry: true,
+		},
+		{
+			Country:        "XX",
+			InvalidCountry: true,
+		},
+	}
+
+	for i, testCase := range testCases {
+
+		address := New(
+			WithStreetAddress([]string{
+				"1 Queen Street",
+			}),
+			WithLocality("Melbourne"),
+			WithAdministrativeArea("VIC"),
+			WithPostCode("3000"),
+			WithCountry(testCase.Country),
+		)
+
+		err := registry.Validate(address)
+
+		if errors.Is(err, ErrCountryNotCompiledIn) != testCase.NotCompiledIn {
+			t.Errorf("Expected ErrCountryNotCompiledIn to be %t for test case %d, got %v", testCase.NotCompiledIn, i, err)
+		}
+
+		if errors.Is(err, ErrInvalidCountryCode) != testCase.InvalidCountry {
+			t.Errorf("Expected ErrInvalidCountryCode to be %t for test case %d, got %v", testCase.InvalidCountry, i, err)
+		}
+	}
+}
+
+func TestRegistryAdministrativeAreaRedirects(t *testing.T) {
+
+	china := GetCountry("CN")
+	china.AdministrativeAreaRedirects = []AdministrativeAreaRedirect{
+		{ID: "91", Name: "香港", PostalKey: "香港", LatinizedName: "Hong Kong", CountryCode: "HK"},
+	}
+
+	registry := NewRegistry(WithCountryData("CN", "", china))
+
+	testCases := []struct {
+		AdministrativeArea string
+		CountryCode        string
+	}{
+		{AdministrativeArea: "91", CountryCode: "HK"},
+		{AdministrativeArea: "香港", CountryCode: "HK"},
+		{AdministrativeArea: "hong kong", CountryCode: "HK"},
+		{AdministrativeArea: "11"},
+		{AdministrativeArea: ""},
+	}
+
+	for i, testCase := range testCases {
+
+		address := New(
+			WithCountry("CN"),
+			WithAdministrativeArea(testCase.AdministrativeArea),
+			WithLocality("中环"),
+			WithStreetAddress([]string{"皇后大道中 1 号"}),
+			WithPostCode("100000"),
+		)
+
+		err := registry.Validate(address)
+
+		var redirectErr ErrAdministrativeAreaIsCountry
+
+		isRedirect := errors.As(err, &redirectErr)
+
+		if isRedirect != (testCase.CountryCode != "") {
+			t.Errorf("Unexpected redirect error result for test case %d: %v", i, err)
+		}
+
+		if isRedirect && (redirectErr.CountryCode != testCase.CountryCode || !errors.Is(err, ErrInvalidAdministrativeArea)) {
+			t.Errorf("Expected an invalid administrative area redirecting to %s for test case %d, got %v", testCase.CountryCode, i, err)
+		}
+
+		normalized := registry.NormalizeCountry(address)
+
+		if testCase.CountryCode == "" {
+
+			if !reflect.DeepEqual(normalized, address) {
+				t.Errorf("Expected the address to be unchanged for test case %d, got %+v", i, normalized)
+			}
+
+			continue
+		}
+
+		if normalized.Country != testCase.CountryCode || normalized.AdministrativeArea != "" || normalized.Locality != address.Locality {
+			t.Errorf("Expected the address to be rewritten to %s for test case %d, got %+v", testCase.CountryCode, i, normalized)
+		}
+	}
+
+	if redirects := registry.GetCountry("CN").AdministrativeAreaRedirects; !reflect.DeepEqual(redirects, china.AdministrativeAreaRedirects) {
+		t.Errorf("Expected redirects %+v, got %+v", china.AdministrativeAreaRedirects, redirects)
+	}
+}
+
+func TestRegistryConverters(t *testing.T) {
+
+	registry := NewRegistry(WithCountryData("XW", "Central Warehouse", CountryData{
+		Format:          "%N%n%O%n%A%n%C",
+		Required:        []Field{Locality, StreetAddress},
+		Allowed:         []Field{Locality, Name, Organization, StreetAddress},
+		DefaultLanguage: "en",
+	}))
+
+	address := New(
+		WithName("Receiving"),
+		WithStreetAddress([]string{
+			"Dock 4",
+		}),
+		WithLocality("Building B"),
+		WithCountry("XW"),
+	)
+
+	countryData := registry.GetCountry("XW")
+
+	testCases := []struct {
+		Country         string
+		ExpectedCountry string
+		ToAddress       func() (Address, error)
+	}{
+		{
+			Country:         registry.ToVCardADR(address, VCardOptions{Language: "en"}).Country,
+			ExpectedCountry: "Central Warehouse",
+			ToAddress: func() (Address, error) {
+				return registry.AddressFromVCardADR(registry.ToVCardADR(address, VCardOptions{Language: "en"}))
+			},
+		},
+		{
+			Country:         registry.ToPostalAddress(address, "en").AddressCountry,
+			ExpectedCountry: "XW",
+			ToAddress: func() (Address, error) {
+				return registry.AddressFromPostalAddress(registry.ToPostalAddress(address, "en"))
+			},
+		},
+		{
+			Country:         registry.ToXAL(address, countryData, "en").Country.CountryName,
+			ExpectedCountry: "Central Warehouse",
+			ToAddress: func() (Address, error) {
+
+				var b bytes.Buffer
+
+				if err := registry.EncodeXAL(&b, address, countryData, "en"); err != nil {
+					return Address{}, err
+				}
+
+				return registry.DecodeXAL(&b)
+			},
+		},
+		{
+			Country:         registry.ToS42(address, countryData, "en").CountryName,
+			ExpectedCountry: "Central Warehouse",
+			ToAddress: func() (Address, error) {
+
+				var b bytes.Buffer
+
+				if err := registry.EncodeS42(&b, address, countryData, "en"); err != nil {
+					return Address{}, err
+				}
+
+				return registry.DecodeS42(&b)
+			},
+		},
+	}
+
+	for i, testCase := range testCases {
+
+		if testCase.Country != testCase.ExpectedCountry {
+			t.Errorf("Expected the country of test case %d to be %q, got %q", i, testCase.ExpectedCountry, testCase.Country)
+		}
+
+		converted, err := testCase.ToAddress()
+
+		if err != nil {
+			t.Errorf("Unexpected error converting test case %d back into an address: %s", i, err)
+			continue
+		}
+
+		if converted.Country != "XW" || converted.Locality != "Building B" {
+			t.Errorf("Expected test case %d to convert back into the custom territory, got %+v", i, converted)
+		}
+	}
+
+	if _, err := ToVCardADR(address, VCardOptions{}).ToAddress(); !errors.Is(err, ErrInvalidCountryCode) {
+		t.Errorf("Expected the custom territory to be unknown to the default registry, got %v", err)
+	}
+}
@@ -0,0 +1,193 @@
+package address
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// S42Address contains the address elements defined by the UPU S42 international postal address components and
+// templates standard. The addressee's name and organization are in the Addressee element, the street address lines
+// are in the DeliveryPoint element, the dependent locality and locality (town) are in the Locality element and the
+// administrative area is in the Region element along with its key.
+type S42Address struct {
+	XMLName       xml.Name          `xml:"Address"`
+	CountryCode   string            `xml:"CountryCode"`
+	CountryName   string            `xml:"CountryName,omitempty"`
+	Addressee     *S42Addressee     `xml:"Addressee,omitempty"`
+	DeliveryPoint *S42DeliveryPoint `xml:"DeliveryPoint,omitempty"`
+	Locality      *S42Locality      `xml:"Locality,omitempty"`
+	Region        *S42Region        `xml:"Region,omitempty"`
+	Postcode      string            `xml:"Postcode,omitempty"`
+	SortingCode   string            `xml:"SortingCode,omitempty"`
+}
+
+// S42Addressee contains the addressee's name and organization.
+type S42Addressee struct {
+	Name             string `xml:"Name,omitempty"`
+	OrganisationName string `xml:"OrganisationName,omitempty"`
+}
+
+// S42DeliveryPoint contains the street address lines of an address.
+type S42DeliveryPoint struct {
+	AddressLine []string `xml:"AddressLine"`
+}
+
+// S42Locality contains the dependent locality and the locality (town) of an address.
+type S42Locality struct {
+	DependentLocality string `xml:"DependentLocality,omitempty"`
+	Town              string `xml:"Town,omitempty"`
+}
+
+// S42Region contains the name of the administrative area of an address, with its key in the Code attribute.
+type S42Region struct {
+	Code string `xml:"code,attr,omitempty"`
+	Name string `xml:",chardata"`
+}
+
+// ToS42 converts an address into UPU S42 address elements. The country data is used to get the names of the
+// administrative area, locality and dependent locality in the given language. If the language does not have any
+// translations, the country's default language is used.
+func ToS42(address Address, countryData CountryData, language string) S42Address {
+	return defaultRegistry.ToS42(address, countryData, language)
+}
+
+// ToS42 converts an address into UPU S42 address elements, using the registry's data for the name of the country.
+// See ToS42 for details.
+func (r *Registry) ToS42(address Address, countryData CountryData, language string) S42Address {
+
+	names := countryData.subdivisionNames(address, language)
+
+	s42 := S42Address{
+		CountryCode: address.Country,
+		CountryName: r.countryName(address.Country, language),
+		Postcode:    strings.TrimSpace(address.PostCode),
+		SortingCode: strings.TrimSpace(address.SortingCode),
+	}
+
+	if address.Name != "" || address.Organization != "" {
+		s42.Addressee = &S42Addressee{
+			Name:             strings.TrimSpace(address.Name),
+			OrganisationName: strings.TrimSpace(address.Organization),
+		}
+	}
+
+	var streetAddress []string
+
+	for _, line := range address.StreetAddress {
+		if strings.TrimSpace(line) != "" {
+			streetAddress = append(streetAddress, strings.TrimSpace(line))
+		}
+	}
+
+	if len(streetAddress) > 0 {
+		s42.DeliveryPoint = &S42DeliveryPoint{
+			AddressLine: streetAddress,
+		}
+	}
+
+	if names.locality != "" || names.dependentLocality != "" {
+		s42.Locality = &S42Locality{
+			DependentLocality: names.dependentLocality,
+			Town:              names.locality,
+		}
+	}
+
+	if names.administrativeArea != "" {
+		s42.Region = &S42Region{
+			Code: address.AdministrativeArea,
+			Name: names.administrativeArea,
+		}
+	}
+
+	return s42
+}
+
+// EncodeS42 writes an address as UPU S42 address elements. See ToS42 for details.
+func EncodeS42(w io.Writer, address Address, countryData CountryData, language string) error {
+	return defaultRegistry.EncodeS42(w, address, countryData, language)
+}
+
+// EncodeS42 writes an address as UPU S42 address elements using the registry's data. See ToS42 for details.
+func (r *Registry) EncodeS42(w io.Writer, address Address, countryData CountryData, language string) error {
+
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+
+	return encoder.Encode(r.ToS42(address, countryData, language))
+}
+
+// DecodeS42 reads UPU S42 address elements and converts them into an address. The administrative area, locality and
+// dependent locality are converted into their keys where possible, and the address is validated.
+// In the case where the address is invalid, the address is returned along with the validation error.
+func DecodeS42(r io.Reader) (Address, error) {
+	return defaultRegistry.DecodeS42(r)
+}
+
+// DecodeS42 reads UPU S42 address elements and converts them into an address using the registry's data and
+// overrides. See DecodeS42 for details.
+func (r *Registry) DecodeS42(reader io.Reader) (Address, error) {
+
+	s42 := S42Address{}
+
+	if err := xml.NewDecoder(reader).Decode(&s42); err != nil {
+		return Address{}, fmt.Errorf("%w: %w", ErrInvalidXML, err)
+	}
+
+	return r.AddressFromS42(s42)
+}
+
+// ToAddress converts the S42 address elements into an address. See DecodeS42 for details.
+func (s S42Address) ToAddress() (Address, error) {
+	return defaultRegistry.AddressFromS42(s)
+}
+
+// AddressFromS42 converts S42 address elements into an address using the registry's data and overrides. See
+// DecodeS42 for details.
+func (r *Registry) AddressFromS42(s S42Address) (Address, error) {
+
+	countryCode := r.data.getCountryCode(s.CountryCode)
+
+	if countryCode == "" {
+		countryCode = r.data.getCountryCode(s.CountryName)
+	}
+
+	if countryCode == "" {
+		return Address{}, ErrInvalidCountryCode
+	}
+
+	address := Address{
+		Country:     countryCode,
+		PostCode:    strings.TrimSpace(s.Postcode),
+		SortingCode: strings.TrimSpace(s.SortingCode),
+	}
+
+	if s.Addressee != nil {
+		address.Name = strings.TrimSpace(s.Addressee.Name)
+		address.Organization = strings.TrimSpace(s.Addressee.OrganisationName)
+	}
+
+	if s.DeliveryPoint != nil {
+		for _, line := range s.DeliveryPoint.AddressLine {
+			if strings.TrimSpace(line) != "" {
+				address.StreetAddress = append(address.StreetAddress, strings.TrimSpace(line))
+			}
+		}
+	}
+
+	if s.Locality != nil {
+		address.DependentLocality = strings.TrimSpace(s.Locality.DependentLocality)
+		address.Locality = strings.TrimSpace(s.Locality.Town)
+	}
+
+	if s.Region != nil {
+		address.AdministrativeArea = strings.TrimSpace(s.Region.Code)
+
+		if address.AdministrativeArea == "" {
+			address.AdministrativeArea = strings.TrimSpace(s.Region.Name)
+		}
+	}
+
+	return r.resolveSubdivisionsAndValidate(address)
+}
@@ -0,0 +1,142 @@
+package address
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestS42(t *testing.T) {
+
+	testCases := []struct {
+		Address  []func(*Address)
+		Language string
+		Expected string
+	}{
+		{
+			Address: []func(*Address){
+				WithName("John Smith"),
+				WithOrganization("Company Pty Ltd"),
+				WithStreetAddress([]string{
+					"Suite 7, 9th Floor",
+					"525 Collins Street",
+				}),
+				WithLocality("Melbourne"),
+				WithAdministrativeArea("VIC"),
+				WithPostCode("3000"),
+				WithCountry("AU"),
+			},
+			Language: "en",
+			Expected: `<Address>
+  <CountryCode>AU</CountryCode>
+  <CountryName>Australia</CountryName>
+  <Addressee>
+    <Name>John Smith</Name>
+    <OrganisationName>Company Pty Ltd</OrganisationName>
+  </Addressee>
+  <DeliveryPoint>
+    <AddressLine>Suite 7, 9th Floor</AddressLine>
+    <AddressLine>525 Collins Street</AddressLine>
+  </DeliveryPoint>
+  <Locality>
+    <Town>Melbourne</Town>
+  </Locality>
+  <Region code="VIC">Victoria</Region>
+  <Postcode>3000</Postcode>
+</Address>`,
+		},
+		{
+			Address: []func(*Address){
+				WithName("司馬遷"),
+				WithStreetAddress([]string{
+					"1 西河北路",
+				}),
+				WithDependentLocality("临翔区"),
+				WithLocality("临沧市"),
+				WithAdministrativeArea("53"),
+				WithPostCode("677000"),
+				WithCountry("CN"),
+			},
+			Language: "zh",
+			Expected: `<Address>
+  <CountryCode>CN</CountryCode>
+  <CountryName>中国</CountryName>
+  <Addressee>
+    <Name>司馬遷</Name>
+  </Addressee>
+  <DeliveryPoint>
+    <AddressLine>1 西河北路</AddressLine>
+  </DeliveryPoint>
+  <Locality>
+    <DependentLocality>临翔区</DependentLocality>
+    <Town>临沧市</Town>
+  </Locality>
+  <Region code="53">云南省</Region>
+  <Postcode>677000</Postcode>
+</Address>`,
+		},
+	}
+
+	for i, testCase := range testCases {
+
+		address, err := NewValid(testCase.Address...)
+
+		if err != nil {
+			t.Fatalf("Error creating valid address using test case %d: %s", i, err)
+		}
+
+		buf := bytes.NewBuffer([]byte{})
+
+		err = EncodeS42(buf, address, GetCountry(address.Country), testCase.Language)
+
+		if err != nil {
+			t.Fatalf("Error encoding test case %d: %s", i, err)
+		}
+
+		if buf.String() != testCase.Expected {
+			t.Errorf("S42 address for test case %d does not match the expected result, got %s", i, buf.String())
+		}
+
+		decoded, err := DecodeS42(buf)
+
+		if err != nil {
+			t.Fatalf("Error decoding test case %d: %s", i, err)
+		}
+
+		if !reflect.DeepEqual(decoded, address) {
+			t.Errorf("Decoded address for test case %d does not match, got %+v", i, decoded)
+		}
+	}
+}
+
+func TestDecodeS42Errors(t *testing.T) {
+
+	testCases := []struct {
+		XML         string
+		ExpectedErr error
+	}{
+		{
+			XML:         `<Address><CountryCode>AU`,
+			ExpectedErr: ErrInvalidXML,
+		},
+		{
+			XML:         `<Address><CountryName>Atlantis</CountryName></Address>`,
+			ExpectedErr: ErrInvalidCountryCode,
+		},
+		{
+			XML:         `<Address><CountryCode>AU</CountryCode><DeliveryPoint><AddressLine>525 Collins Street</AddressLine></DeliveryPoint><Locality><Town>Melbourne</Town></Locality><Region>Victoria</Region><Postcode>30000</Postcode></Address>`,
+			ExpectedErr: ErrInvalidPostCode,
+		},
+	}
+
+	for i, testCase := range testCases {
+
+		_, err := DecodeS42(strings.NewReader(testCase.XML))
+
+		if !errors.Is(err, testCase.ExpectedErr) {
+			t.Errorf("Expected error %v for test case %d, got %v", testCase.ExpectedErr, i, err)
+		}
+	}
+}
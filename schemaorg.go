@@ -0,0 +1,164 @@
+package address
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// PostalAddress is a schema.org PostalAddress (https://schema.org/PostalAddress). It can be marshaled into JSON-LD
+// using encoding/json.
+type PostalAddress struct {
+	Context         string `json:"@context,omitempty"`
+	Type            string `json:"@type"`
+	Name            string `json:"name,omitempty"`
+	StreetAddress   string `json:"streetAddress,omitempty"`
+	AddressLocality string `json:"addressLocality,omitempty"`
+	AddressRegion   string `json:"addressRegion,omitempty"`
+	PostalCode      string `json:"postalCode,omitempty"`
+	AddressCountry  string `json:"addressCountry,omitempty"`
+}
+
+// ToPostalAddress converts an address into a schema.org PostalAddress. The language is used for the names of the
+// locality and administrative area. The street address lines are joined using `\n`s and the country is the ISO 3166-1
+// country code. Where the administrative area has a postal key in the latin alphabet (for example, VIC or CA), it is
+// used instead of the name of the administrative area. The organization, dependent locality and sorting code do not
+// have PostalAddress properties, so they are left out.
+func ToPostalAddress(address Address, language string) PostalAddress {
+	return defaultRegistry.ToPostalAddress(address, language)
+}
+
+// ToPostalAddress converts an address into a schema.org PostalAddress using the registry's data and overrides. See
+// ToPostalAddress for details.
+func (r *Registry) ToPostalAddress(address Address, language string) PostalAddress {
+
+	language = r.normalizeLanguage(address.Country, language)
+
+	data := address.toFormatData(r, r.getCountry(address.Country), language)
+
+	if isAlphabetRegex.MatchString(data.AdministrativeAreaPostalKey) {
+		data.AdministrativeArea = data.AdministrativeAreaPostalKey
+	}
+
+	return PostalAddress{
+		Context:         "https://schema.org",
+		Type:            "PostalAddress",
+		Name:            strings.TrimSpace(address.Name),
+		StreetAddress:   strings.Join(data.StreetAddress, "\n"),
+		AddressLocality: data.Locality,
+		AddressRegion:   data.AdministrativeArea,
+		PostalCode:      data.PostCode,
+		AddressCountry:  address.Country,
+	}
+}
+
+// UnmarshalJSON unmarshals a PostalAddress from JSON-LD. The addressCountry property can either be text or a
+// schema.org Country.
+func (p *PostalAddress) UnmarshalJSON(data []byte) error {
+
+	type postalAddress PostalAddress
+
+	var raw struct {
+		postalAddress
+		AddressCountry json.RawMessage `json:"addressCountry,omitempty"`
+	}
+
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	*p = PostalAddress(raw.postalAddress)
+
+	if len(raw.AddressCountry) == 0 {
+		return nil
+	}
+
+	if err := json.Unmarshal(raw.AddressCountry, &p.AddressCountry); err == nil {
+		return nil
+	}
+
+	var country struct {
+		Name       string `json:"name"`
+		Identifier string `json:"identifier"`
+	}
+
+	if err := json.Unmarshal(raw.AddressCountry, &country); err != nil {
+		return err
+	}
+
+	p.AddressCountry = country.Name
+
+	if country.Identifier != "" {
+		p.AddressCountry = country.Identifier
+	}
+
+	return nil
+}
+
+// ParsePostalAddress parses a schema.org PostalAddress from JSON-LD. The JSON-LD can either be a PostalAddress or a
+// node (such as an Organization or Place) with a PostalAddress in its address property.
+func ParsePostalAddress(jsonLD []byte) (PostalAddress, error) {
+
+	var node struct {
+		Type    string          `json:"@type"`
+		Address json.RawMessage `json:"address"`
+	}
+
+	if err := json.Unmarshal(jsonLD, &node); err != nil {
+		return PostalAddress{}, fmt.Errorf("%w: %w", ErrInvalidPostalAddress, err)
+	}
+
+	if node.Type != "PostalAddress" && len(node.Address) > 0 {
+		return ParsePostalAddress(node.Address)
+	}
+
+	if node.Type != "PostalAddress" {
+		return PostalAddress{}, fmt.Errorf("%w: unexpected type %q", ErrInvalidPostalAddress, node.Type)
+	}
+
+	var postalAddress PostalAddress
+
+	if err := json.Unmarshal(jsonLD, &postalAddress); err != nil {
+		return postalAddress, fmt.Errorf("%w: %w", ErrInvalidPostalAddress, err)
+	}
+
+	return postalAddress, nil
+}
+
+// ToAddress converts the PostalAddress into an address. The addressCountry can either be an ISO 3166-1 country code
+// or the name of the country in English or the country's default language. The addressRegion and addressLocality are
+// converted into the keys of the country's administrative areas and localities where possible.
+// The address is not validated. If the country cannot be determined, ErrInvalidCountryCode is returned.
+func (p PostalAddress) ToAddress() (Address, error) {
+	return defaultRegistry.AddressFromPostalAddress(p)
+}
+
+// AddressFromPostalAddress converts a PostalAddress into an address using the registry's data. See
+// PostalAddress.ToAddress for details.
+func (r *Registry) AddressFromPostalAddress(p PostalAddress) (Address, error) {
+
+	countryCode := r.data.getCountryCode(p.AddressCountry)
+
+	if countryCode == "" {
+		return Address{}, ErrInvalidCountryCode
+	}
+
+	var streetAddress []string
+
+	for _, line := range strings.Split(p.StreetAddress, "\n") {
+		if strings.TrimSpace(line) != "" {
+			streetAddress = append(streetAddress, strings.TrimSpace(line))
+		}
+	}
+
+	address := New(
+		WithCountry(countryCode),
+		WithName(strings.TrimSpace(p.Name)),
+		WithStreetAddress(streetAddress),
+		WithLocality(strings.TrimSpace(p.AddressLocality)),
+		WithAdministrativeArea(strings.TrimSpace(p.AddressRegion)),
+		WithPostCode(strings.TrimSpace(p.PostalCode)),
+	)
+
+	return r.data.resolveSubdivisions(address), nil
+}
@@ -0,0 +1,136 @@
+package address
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestToPostalAddress(t *testing.T) {
+
+	testCases := []struct {
+		Address  []func(*Address)
+		Expected string
+	}{
+		{
+			Address: []func(*Address){
+				WithName("John Smith"),
+				WithOrganization("Company Pty Ltd"),
+				WithStreetAddress([]string{
+					"Suite 7, 9th Floor",
+					"525 Collins Street",
+				}),
+				WithLocality("Melbourne"),
+				WithAdministrativeArea("VIC"),
+				WithPostCode("3000"),
+				WithCountry("AU"),
+			},
+			Expected: `{"@context":"https://schema.org","@type":"PostalAddress","name":"John Smith","streetAddress":"Suite 7, 9th Floor\n525 Collins Street","addressLocality":"Melbourne","addressRegion":"VIC","postalCode":"3000","addressCountry":"AU"}`,
+		},
+		{
+			Address: []func(*Address){
+				WithStreetAddress([]string{
+					"1 西河北路",
+				}),
+				WithDependentLocality("临翔区"),
+				WithLocality("临沧市"),
+				WithAdministrativeArea("53"),
+				WithPostCode("677000"),
+				WithCountry("CN"),
+			},
+			Expected: `{"@context":"https://schema.org","@type":"PostalAddress","streetAddress":"1 西河北路","addressLocality":"临沧市","addressRegion":"云南省","postalCode":"677000","addressCountry":"CN"}`,
+		},
+	}
+
+	for i, testCase := range testCases {
+
+		address, err := NewValid(testCase.Address...)
+
+		if err != nil {
+			t.Fatalf("Error creating valid address using test case %d: %s", i, err)
+		}
+
+		jsonLD, err := json.Marshal(ToPostalAddress(address, ""))
+
+		if err != nil {
+			t.Fatalf("Error marshaling test case %d: %s", i, err)
+		}
+
+		if string(jsonLD) != testCase.Expected {
+			t.Errorf("JSON-LD for test case %d does not match the expected result, got %s", i, jsonLD)
+		}
+
+		postalAddress, err := ParsePostalAddress(jsonLD)
+
+		if err != nil {
+			t.Fatalf("Error parsing JSON-LD for test case %d: %s", i, err)
+		}
+
+		converted, err := postalAddress.ToAddress()
+
+		if err != nil {
+			t.Fatalf("Error converting test case %d to an address: %s", i, err)
+		}
+
+		expected := address
+		expected.Organization = ""
+		expected.DependentLocality = ""
+
+		if !reflect.DeepEqual(converted, expected) {
+			t.Errorf("Converted address for test case %d does not match, got %+v", i, converted)
+		}
+	}
+}
+
+func TestParsePostalAddress(t *testing.T) {
+
+	testCases := []struct {
+		JSONLD      string
+		Expected    Address
+		ExpectedErr error
+	}{
+		{
+			JSONLD: `{"@context":"https://schema.org","@type":"Organization","name":"Microsoft","address":{"@type":"PostalAddress","streetAddress":"1 Microsoft Way","addressLocality":"Redmond","addressRegion":"Washington","postalCode":"98052","addressCountry":{"@type":"Country","name":"United States"}}}`,
+			Expected: Address{
+				Country:            "US",
+				StreetAddress:      []string{"1 Microsoft Way"},
+				Locality:           "Redmond",
+				AdministrativeArea: "WA",
+				PostCode:           "98052",
+			},
+		},
+		{
+			JSONLD:      `{"@type":"Organization","name":"Microsoft"}`,
+			ExpectedErr: ErrInvalidPostalAddress,
+		},
+		{
+			JSONLD:      `[]`,
+			ExpectedErr: ErrInvalidPostalAddress,
+		},
+		{
+			JSONLD:      `{"@type":"PostalAddress","addressCountry":"Atlantis"}`,
+			ExpectedErr: ErrInvalidCountryCode,
+		},
+	}
+
+	for i, testCase := range testCases {
+
+		postalAddress, err := ParsePostalAddress([]byte(testCase.JSONLD))
+
+		var address Address
+
+		if err == nil {
+			address, err = postalAddress.ToAddress()
+		}
+
+		if !errors.Is(err, testCase.ExpectedErr) {
+			t.Errorf("Expected error %v for test case %d, got %v", testCase.ExpectedErr, i, err)
+			continue
+		}
+
+		if testCase.ExpectedErr == nil && !reflect.DeepEqual(address, testCase.Expected) {
+			t.Errorf("Parsed address for test case %d does not match the expected result, got %+v", i, address)
+		}
+	}
+}
@@ -0,0 +1,343 @@
+package address
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Transliterator converts text in other scripts into the latin alphabet. Text that is already in the latin alphabet
+// or in scripts the Transliterator does not handle should be returned unchanged.
+type Transliterator interface {
+	Transliterate(text string) string
+}
+
+// TransliteratorFunc is a function that implements the Transliterator interface.
+type TransliteratorFunc func(text string) string
+
+// Transliterate calls the function to transliterate the text.
+func (t TransliteratorFunc) Transliterate(text string) string {
+	return t(text)
+}
+
+// Transliterators combines Transliterators into a single Transliterator, which runs them in order.
+func Transliterators(transliterators ...Transliterator) Transliterator {
+	return TransliteratorFunc(func(text string) string {
+
+		for _, transliterator := range transliterators {
+			text = transliterator.Transliterate(text)
+		}
+
+		return text
+	})
+}
+
+var (
+	// CyrillicTransliterator romanizes the Cyrillic script used by Russian, Ukrainian, Belarusian and Bulgarian
+	// using the ICAO Doc 9303 scheme, which is used in machine readable passports.
+	CyrillicTransliterator Transliterator = TransliteratorFunc(transliterateCyrillic)
+
+	// GreekTransliterator romanizes the Greek script using the ELOT 743 scheme, without the diacritics.
+	GreekTransliterator Transliterator = TransliteratorFunc(transliterateGreek)
+
+	// HangulTransliterator romanizes Korean Hangul using the Revised Romanization of Korean. Each syllable is romanized
+	// on its own, so the sound change rules are not applied.
+	HangulTransliterator Transliterator = TransliteratorFunc(transliterateHangul)
+
+	// KanaTransliterator romanizes Japanese hiragana and katakana using the Hepburn system, without macrons for long
+	// vowels. Kanji cannot be romanized without a dictionary, so they are left unchanged.
+	KanaTransliterator Transliterator = TransliteratorFunc(transliterateKana)
+
+	// DefaultTransliterator combines all the built-in Transliterators.
+	DefaultTransliterator = Transliterators(CyrillicTransliterator, GreekTransliterator, HangulTransliterator, KanaTransliterator)
+)
+
+// transliterateRuns replaces each run of runes that are in the script with the result of convert. If capitalize is true,
+// the first letter of runs at the start of a word are converted to uppercase.
+func transliterateRuns(text string, inScript func(r rune) bool, convert func(run []rune) string, capitalize bool) string {
+
+	var b strings.Builder
+
+	var run []rune
+
+	wordStart := true
+
+	flush := func() {
+
+		if len(run) == 0 {
+			return
+		}
+
+		converted := convert(run)
+
+		if capitalize && wordStart && converted != "" {
+			first, size := utf8.DecodeRuneInString(converted)
+			converted = string(unicode.ToUpper(first)) + converted[size:]
+		}
+
+		b.WriteString(converted)
+		run = run[:0]
+	}
+
+	for _, r := range text {
+
+		if inScript(r) {
+
+			if len(run) == 0 {
+				wordStart = b.Len() == 0 || !isWordRune(lastRune(b.String()))
+			}
+
+			run = append(run, r)
+			continue
+		}
+
+		flush()
+		b.WriteRune(r)
+	}
+
+	flush()
+
+	return b.String()
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+func lastRune(text string) rune {
+	r, _ := utf8.DecodeLastRuneInString(text)
+	return r
+}
+
+// transliterateCased converts each rune in a run using a table of lowercase letters. Uppercase letters that become
+// several latin letters are title cased, unless they are next to other uppercase letters.
+func transliterateCased(run []rune, table map[rune]string) string {
+
+	var b strings.Builder
+
+	for i, r := range run {
+
+		lower := unicode.ToLower(r)
+
+		latin, ok := table[lower]
+
+		if !ok {
+			b.WriteRune(r)
+			continue
+		}
+
+		if lower == r || latin == "" {
+			b.WriteString(latin)
+			continue
+		}
+
+		allCaps := (i > 0 && unicode.IsUpper(run[i-1])) || (i < len(run)-1 && unicode.IsUpper(run[i+1]))
+
+		if allCaps {
+			b.WriteString(strings.ToUpper(latin))
+			continue
+		}
+
+		first, size := utf8.DecodeRuneInString(latin)
+		b.WriteString(string(unicode.ToUpper(first)) + latin[size:])
+	}
+
+	return b.String()
+}
+
+var cyrillicTable = map[rune]string{
+	'а': "a", 'б': "b", 'в': "v", 'г': "g", 'ґ': "g", 'д': "d", 'е': "e", 'ё': "e", 'є': "ie", 'ж': "zh", 'з': "z",
+	'и': "i", 'і': "i", 'ї': "i", 'й': "i", 'к': "k", 'л': "l", 'м': "m", 'н': "n", 'о': "o", 'п': "p", 'р': "r",
+	'с': "s", 'т': "t", 'у': "u", 'ў': "u", 'ф': "f", 'х': "kh", 'ц': "ts", 'ч': "ch", 'ш': "sh", 'щ': "shch",
+	'ъ': "ie", 'ы': "y", 'ь': "", 'э': "e", 'ю': "iu", 'я': "ia",
+}
+
+func transliterateCyrillic(text string) string {
+	return transliterateRuns(text, func(r rune) bool {
+		return unicode.Is(unicode.Cyrillic, r)
+	}, func(run []rune) string {
+		return transliterateCased(run, cyrillicTable)
+	}, false)
+}
+
+var greekTable = map[rune]string{
+	'α': "a", 'ά': "a", 'β': "v", 'γ': "g", 'δ': "d", 'ε': "e", 'έ': "e", 'ζ': "z", 'η': "i", 'ή': "i", 'θ': "th",
+	'ι': "i", 'ί': "i", 'ϊ': "i", 'ΐ': "i", 'κ': "k", 'λ': "l", 'μ': "m", 'ν': "n", 'ξ': "x", 'ο': "o", 'ό': "o",
+	'π': "p", 'ρ': "r", 'σ': "s", 'ς': "s", 'τ': "t", 'υ': "y", 'ύ': "y", 'ϋ': "y", 'ΰ': "y", 'φ': "f", 'χ': "ch",
+	'ψ': "ps", 'ω': "o", 'ώ': "o",
+}
+
+// greekDigraphs are the pairs of vowels that are romanized together.
+var greekDigraphs = map[string]string{
+	"ου": "ou", "ού": "ou", "αυ": "av", "αύ": "av", "ευ": "ev", "εύ": "ev",
+}
+
+func transliterateGreek(text string) string {
+	return transliterateRuns(text, func(r rune) bool {
+		return unicode.Is(unicode.Greek, r)
+	}, func(run []rune) string {
+
+		var b strings.Builder
+
+		for i := 0; i < len(run); i++ {
+
+			if i < len(run)-1 {
+
+				pair := []rune{run[i], run[i+1]}
+
+				if latin, ok := greekDigraphs[strings.ToLower(string(pair))]; ok {
+					b.WriteString(transliterateCased(pair, map[rune]string{
+						unicode.ToLower(pair[0]): latin[:1],
+						unicode.ToLower(pair[1]): latin[1:],
+					}))
+					i++
+					continue
+				}
+			}
+
+			b.WriteString(transliterateCased(run[i:i+1], greekTable))
+		}
+
+		return b.String()
+	}, false)
+}
+
+var (
+	hangulInitials = []string{"g", "kk", "n", "d", "tt", "r", "m", "b", "pp", "s", "ss", "", "j", "jj", "ch", "k", "t", "p", "h"}
+	hangulVowels   = []string{"a", "ae", "ya", "yae", "eo", "e", "yeo", "ye", "o", "wa", "wae", "oe", "yo", "u", "wo", "we", "wi", "yu", "eu", "ui", "i"}
+	hangulFinals   = []string{"", "k", "k", "k", "n", "n", "n", "t", "l", "k", "m", "l", "l", "l", "p", "l", "m", "p", "p", "t", "t", "ng", "t", "t", "k", "t", "p", "t"}
+)
+
+const (
+	hangulFirstSyllable = 0xAC00
+	hangulLastSyllable  = 0xD7A3
+)
+
+func transliterateHangul(text string) string {
+	return transliterateRuns(text, func(r rune) bool {
+		return r >= hangulFirstSyllable && r <= hangulLastSyllable
+	}, func(run []rune) string {
+
+		var b strings.Builder
+
+		for _, r := range run {
+
+			index := int(r - hangulFirstSyllable)
+
+			b.WriteString(hangulInitials[index/588])
+			b.WriteString(hangulVowels[(index%588)/28])
+			b.WriteString(hangulFinals[index%28])
+		}
+
+		return b.String()
+	}, true)
+}
+
+var kanaTable = map[rune]string{
+	'あ': "a", 'い': "i", 'う': "u", 'え': "e", 'お': "o",
+	'か': "ka", 'き': "ki", 'く': "ku", 'け': "ke", 'こ': "ko",
+	'が': "ga", 'ぎ': "gi", 'ぐ': "gu", 'げ': "ge", 'ご': "go",
+	'さ': "sa", 'し': "shi", 'す': "su", 'せ': "se", 'そ': "so",
+	'ざ': "za", 'じ': "ji", 'ず': "zu", 'ぜ': "ze", 'ぞ': "zo",
+	'た': "ta", 'ち': "chi", 'つ': "tsu", 'て': "te", 'と': "to",
+	'だ': "da", 'ぢ': "ji", 'づ': "zu", 'で': "de", 'ど': "do",
+	'な': "na", 'に': "ni", 'ぬ': "nu", 'ね': "ne", 'の': "no",
+	'は': "ha", 'ひ': "hi", 'ふ': "fu", 'へ': "he", 'ほ': "ho",
+	'ば': "ba", 'び': "bi", 'ぶ': "bu", 'べ': "be", 'ぼ': "bo",
+	'ぱ': "pa", 'ぴ': "pi", 'ぷ': "pu", 'ぺ': "pe", 'ぽ': "po",
+	'ま': "ma", 'み': "mi", 'む': "mu", 'め': "me", 'も': "mo",
+	'や': "ya", 'ゆ': "yu", 'よ': "yo",
+	'ら': "ra", 'り': "ri", 'る': "ru", 'れ': "re", 'ろ': "ro",
+	'わ': "wa", 'ゐ': "i", 'ゑ': "e", 'を': "o", 'ん': "n", 'ゔ': "vu",
+	'ぁ': "a", 'ぃ': "i", 'ぅ': "u", 'ぇ': "e", 'ぉ': "o", 'ゎ': "wa", 'ゕ': "ka", 'ゖ': "ke",
+}
+
+// kanaSmallVowels are the small kana that change the vowel of the previous kana, such as ファ (fa).
+var kanaSmallVowels = map[rune]string{
+	'ぁ': "a", 'ぃ': "i", 'ぅ': "u", 'ぇ': "e", 'ぉ': "o",
+}
+
+// kanaYouon are the small kana that combine with the previous kana, such as きゃ (kya).
+var kanaYouon = map[rune]string{
+	'ゃ': "a", 'ゅ': "u", 'ょ': "o",
+}
+
+const (
+	hiraganaFirst  = 0x3041
+	hiraganaLast   = 0x3096
+	katakanaFirst  = 0x30A1
+	katakanaLast   = 0x30F6
+	katakanaOffset = katakanaFirst - hiraganaFirst
+	kanaSokuon     = 'っ'
+	kanaLongVowel  = 'ー'
+)
+
+func isKana(r rune) bool {
+	return (r >= hiraganaFirst && r <= hiraganaLast) || (r >= katakanaFirst && r <= katakanaLast) || r == kanaLongVowel
+}
+
+func transliterateKana(text string) string {
+	return transliterateRuns(text, isKana, func(run []rune) string {
+
+		var syllables []string
+
+		sokuon := false
+
+		for _, r := range run {
+
+			if r >= katakanaFirst && r <= katakanaLast {
+				r -= katakanaOffset
+			}
+
+			if r == kanaLongVowel {
+				continue
+			}
+
+			if r == kanaSokuon {
+				sokuon = true
+				continue
+			}
+
+			last := len(syllables) - 1
+
+			if vowel, ok := kanaYouon[r]; ok && last >= 0 && strings.HasSuffix(syllables[last], "i") && len(syllables[last]) > 1 {
+
+				base := strings.TrimSuffix(syllables[last], "i")
+
+				if base == "sh" || base == "ch" || base == "j" {
+					syllables[last] = base + vowel
+				} else {
+					syllables[last] = base + "y" + vowel
+				}
+
+				continue
+			}
+
+			if vowel, ok := kanaSmallVowels[r]; ok && last >= 0 && len(syllables[last]) > 1 {
+				syllables[last] = strings.TrimRight(syllables[last], "aiueo") + vowel
+				continue
+			}
+
+			syllable, ok := kanaTable[r]
+
+			if !ok {
+				syllable = kanaYouon[r]
+			}
+
+			if sokuon && syllable != "" {
+
+				if strings.HasPrefix(syllable, "ch") {
+					syllable = "t" + syllable
+				} else if !strings.ContainsAny(syllable[:1], "aiueon") {
+					syllable = syllable[:1] + syllable
+				}
+
+				sokuon = false
+			}
+
+			syllables = append(syllables, syllable)
+		}
+
+		return strings.Join(syllables, "")
+	}, true)
+}
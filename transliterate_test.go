@@ -0,0 +1,54 @@
+package address
+
+import "testing"
+
+func TestTransliterators(t *testing.T) {
+
+	testCases := []struct {
+		Transliterator Transliterator
+		Text           string
+		Expected       string
+	}{
+		{
+			Transliterator: CyrillicTransliterator,
+			Text:           "ул. Щорса, д. 5",
+			Expected:       "ul. Shchorsa, d. 5",
+		},
+		{
+			Transliterator: CyrillicTransliterator,
+			Text:           "МОСКВА, Юрий Жуков",
+			Expected:       "MOSKVA, Iurii Zhukov",
+		},
+		{
+			Transliterator: GreekTransliterator,
+			Text:           "Οδός Ευριπίδου 12, Αθήνα",
+			Expected:       "Odos Evripidou 12, Athina",
+		},
+		{
+			Transliterator: HangulTransliterator,
+			Text:           "서울 종로구 세종대로 175",
+			Expected:       "Seoul Jongrogu Sejongdaero 175",
+		},
+		{
+			Transliterator: KanaTransliterator,
+			Text:           "さっぽろ シャトー ちょっと",
+			Expected:       "Sapporo Shato Chotto",
+		},
+		{
+			Transliterator: KanaTransliterator,
+			Text:           "マッチ ファミリー",
+			Expected:       "Matchi Famiri",
+		},
+		{
+			Transliterator: DefaultTransliterator,
+			Text:           "Иван 東京 ソニー 1-2",
+			Expected:       "Ivan 東京 Soni 1-2",
+		},
+	}
+
+	for i, testCase := range testCases {
+		if transliterated := testCase.Transliterator.Transliterate(testCase.Text); transliterated != testCase.Expected {
+			t.Errorf("Transliterated text for test case %d does not match the expected result, got %q", i, transliterated)
+		}
+	}
+}
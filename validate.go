@@ -3,21 +3,26 @@ package address
 import (
 	"errors"
 	"regexp"
+	"slices"
 	"strings"
 )
 
 // Validate checks and address to determine if it is valid.
 // If you want to create valid addresses, the `address.NewValid()` function does it in one call.
 func Validate(address Address) error {
+	return defaultRegistry.Validate(address)
+}
+
+func validate(r *Registry, address Address) error {
 
 	var errs []error
 
-	if !generated.hasCountry(address.Country) {
-		errs = append(errs, ErrInvalidCountryCode)
+	if err := r.checkCountry(address.Country); err != nil {
+		errs = append(errs, err)
 		return errors.Join(errs...)
 	}
 
-	countryData := generated.getCountry(address.Country)
+	countryData := r.getCountry(address.Country)
 
 	if err := checkRequiredFields(address, countryData.RequiredFields); err != nil {
 		errs = append(errs, err)
@@ -27,7 +32,15 @@ func Validate(address Address) error {
 		errs = append(errs, err)
 	}
 
-	if len(countryData.AdministrativeAreas) > 0 {
+	if redirect, ok := r.data.getAdministrativeAreaRedirect(address.Country, address.AdministrativeArea); ok {
+
+		errs = append(errs, ErrAdministrativeAreaIsCountry{
+			country:            address.Country,
+			AdministrativeArea: address.AdministrativeArea,
+			CountryCode:        redirect.CountryCode,
+		})
+
+	} else if len(countryData.AdministrativeAreas) > 0 {
 
 		if administrativeAreaData, ok := countryData.AdministrativeAreas[countryData.DefaultLanguage]; ok {
 			err := checkSubdivisions(address, administrativeAreaData)
@@ -46,9 +59,37 @@ func Validate(address Address) error {
 		}
 	}
 
+	for _, err := range checkMilitaryAddress(address) {
+
+		if !slices.ContainsFunc(errs, func(e error) bool { return errors.Is(e, err) }) {
+			errs = append(errs, err)
+		}
+	}
+
 	return errors.Join(errs...)
 }
 
+// NormalizeCountry rewrites an address whose administrative area is also a separate country (such as Hong Kong in
+// China) to use the country code of that country, removing the administrative area. Validate returns an
+// ErrAdministrativeAreaIsCountry for these addresses. Other addresses are returned unchanged.
+func NormalizeCountry(address Address) Address {
+	return defaultRegistry.NormalizeCountry(address)
+}
+
+func normalizeCountry(r *Registry, address Address) Address {
+
+	redirect, ok := r.data.getAdministrativeAreaRedirect(address.Country, address.AdministrativeArea)
+
+	if !ok {
+		return address
+	}
+
+	address.Country = redirect.CountryCode
+	address.AdministrativeArea = ""
+
+	return address
+}
+
 func checkRequiredFields(address Address, requiredFields map[Field]struct{}) error {
 
 	errors := ErrMissingRequiredFields{
@@ -114,6 +155,9 @@ func checkRequiredFields(address Address, requiredFields map[Field]struct{}) err
 		return nil
 	}
 
+	// Sort the fields, as the order of the required fields is random
+	slices.Sort(errors.Fields)
+
 	return errors
 }
 
@@ -227,38 +271,38 @@ func checkPostCode(address Address, regex postCodeRegex) error {
 
 	var errs []error
 
-	if address.PostCode != "" && regex.regex != "" {
+	if address.PostCode != "" && regex.Regex != "" {
 
 		country := regex
 
-		countryRegex := regexp.MustCompile(country.regex)
+		countryRegex := regexp.MustCompile(country.Regex)
 
 		if !countryRegex.MatchString(address.PostCode) {
 			errs = append(errs, ErrInvalidPostCode)
 			return errors.Join(errs...)
 		}
 
-		if adminArea, ok := country.subdivisionRegex[address.AdministrativeArea]; ok {
+		if adminArea, ok := country.SubdivisionRegex[address.AdministrativeArea]; ok {
 
-			adminAreaRegex := regexp.MustCompile(adminArea.regex)
+			adminAreaRegex := regexp.MustCompile(adminArea.Regex)
 
 			if !adminAreaRegex.MatchString(address.PostCode) {
 				errs = append(errs, ErrInvalidPostCode)
 				return errors.Join(errs...)
 			}
 
-			if locality, ok := adminArea.subdivisionRegex[address.Locality]; ok {
+			if locality, ok := adminArea.SubdivisionRegex[address.Locality]; ok {
 
-				localityRegex := regexp.MustCompile(locality.regex)
+				localityRegex := regexp.MustCompile(locality.Regex)
 
 				if !localityRegex.MatchString(address.PostCode) {
 					errs = append(errs, ErrInvalidPostCode)
 					return errors.Join(errs...)
 				}
 
-				if dependentLocality, ok := locality.subdivisionRegex[address.DependentLocality]; ok {
+				if dependentLocality, ok := locality.SubdivisionRegex[address.DependentLocality]; ok {
 
-					dependentLocalityRegex := regexp.MustCompile(dependentLocality.regex)
+					dependentLocalityRegex := regexp.MustCompile(dependentLocality.Regex)
 
 					if !dependentLocalityRegex.MatchString(address.PostCode) {
 						errs = append(errs, ErrInvalidPostCode)
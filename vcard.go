@@ -0,0 +1,430 @@
+package address
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// VCardADR contains the seven components of a vCard 4.0 ADR property (RFC 6350) and its LABEL parameter.
+// The post office box and extended address components are deprecated by RFC 6350, so they are left empty when
+// converting an address. When converting back to an address, they are added to the start of the street address.
+type VCardADR struct {
+	POBox           string
+	ExtendedAddress string
+	StreetAddress   []string
+	Locality        string
+	Region          string
+	PostCode        string
+	Country         string
+	Label           string
+}
+
+// VCardOptions configures the conversion of an address into a vCard ADR property.
+// The Language is used for the names of the administrative area and the country. If UseAdministrativeAreaKey is
+// set to true, the region component contains the key of the administrative area instead of its name.
+// If Label is set, it is used to format the address for the LABEL parameter.
+type VCardOptions struct {
+	Language                 string
+	UseAdministrativeAreaKey bool
+	Label                    Formatter
+}
+
+// ToVCardADR converts an address into the components of a vCard ADR property. The name, organization, dependent
+// locality and sorting code do not have a component in the ADR property, so they are only included in the label.
+func ToVCardADR(address Address, options VCardOptions) VCardADR {
+	return defaultRegistry.ToVCardADR(address, options)
+}
+
+// ToVCardADR converts an address into the components of a vCard ADR property using the registry's data and
+// overrides. See ToVCardADR for details.
+func (r *Registry) ToVCardADR(address Address, options VCardOptions) VCardADR {
+
+	language := r.normalizeLanguage(address.Country, options.Language)
+
+	data := address.toFormatData(r, r.getCountry(address.Country), language)
+
+	adr := VCardADR{
+		StreetAddress: data.StreetAddress,
+		Locality:      data.Locality,
+		Region:        data.AdministrativeArea,
+		PostCode:      data.PostCode,
+		Country:       data.Country,
+	}
+
+	if options.UseAdministrativeAreaKey {
+		adr.Region = address.AdministrativeArea
+	}
+
+	if options.Label != nil {
+		adr.Label = options.Label.Format(address, options.Language)
+	}
+
+	return adr
+}
+
+// ToAddress converts the ADR property into an address. The country component can either be an ISO 3166-1 country
+// code or the name of the country in English or the country's default language. The region and locality are
+// converted into the keys of the country's administrative areas and localities where possible.
+// The address is not validated. If the country cannot be determined, ErrInvalidCountryCode is returned.
+func (v VCardADR) ToAddress() (Address, error) {
+	return defaultRegistry.AddressFromVCardADR(v)
+}
+
+// AddressFromVCardADR converts an ADR property into an address using the registry's data. See VCardADR.ToAddress for
+// details.
+func (r *Registry) AddressFromVCardADR(v VCardADR) (Address, error) {
+
+	countryCode := r.data.getCountryCode(v.Country)
+
+	if countryCode == "" {
+		return Address{}, ErrInvalidCountryCode
+	}
+
+	var streetAddress []string
+
+	for _, line := range append([]string{v.POBox, v.ExtendedAddress}, v.StreetAddress...) {
+		if strings.TrimSpace(line) != "" {
+			streetAddress = append(streetAddress, strings.TrimSpace(line))
+		}
+	}
+
+	address := New(
+		WithCountry(countryCode),
+		WithStreetAddress(streetAddress),
+		WithLocality(strings.TrimSpace(v.Locality)),
+		WithAdministrativeArea(strings.TrimSpace(v.Region)),
+		WithPostCode(strings.TrimSpace(v.PostCode)),
+	)
+
+	return r.data.resolveSubdivisions(address), nil
+}
+
+// String returns the value of the ADR property, with the components separated by semicolons and escaped as described
+// in RFC 6350.
+func (v VCardADR) String() string {
+
+	var street []string
+
+	for _, line := range v.StreetAddress {
+		street = append(street, escapeVCardValue(line))
+	}
+
+	return strings.Join([]string{
+		escapeVCardValue(v.POBox),
+		escapeVCardValue(v.ExtendedAddress),
+		strings.Join(street, ","),
+		escapeVCardValue(v.Locality),
+		escapeVCardValue(v.Region),
+		escapeVCardValue(v.PostCode),
+		escapeVCardValue(v.Country),
+	}, ";")
+}
+
+// Property returns the ADR property as a vCard content line, including the LABEL parameter if there is a label.
+// The content line is not folded.
+func (v VCardADR) Property() string {
+
+	if v.Label == "" {
+		return "ADR:" + v.String()
+	}
+
+	return fmt.Sprintf(`ADR;LABEL="%s":%s`, escapeVCardParameter(v.Label), v.String())
+}
+
+// ParseVCardADR parses an ADR property. The property can either be a full content line (for example,
+// `ADR;LABEL="...":;;525 Collins Street;Melbourne;VIC;3000;Australia`) or just the value of the property.
+// Folded content lines must be unfolded before parsing.
+func ParseVCardADR(property string) (VCardADR, error) {
+
+	adr := VCardADR{}
+
+	value := strings.TrimRight(property, "\r\n")
+
+	if strings.HasPrefix(strings.ToUpper(value), "ADR") {
+
+		name, rest, err := splitVCardContentLine(value)
+
+		if err != nil {
+			return adr, err
+		}
+
+		if !strings.EqualFold(name, "ADR") {
+			return adr, fmt.Errorf("%w: unexpected property %s", ErrInvalidVCardADR, name)
+		}
+
+		parameters, v, err := splitVCardParameters(rest)
+
+		if err != nil {
+			return adr, err
+		}
+
+		adr.Label = parameters["LABEL"]
+		value = v
+	}
+
+	components := splitVCardValue(value, ';')
+
+	if len(components) != 7 {
+		return adr, fmt.Errorf("%w: expected 7 components, got %d", ErrInvalidVCardADR, len(components))
+	}
+
+	adr.POBox = unescapeVCardValue(components[0])
+	adr.ExtendedAddress = unescapeVCardValue(components[1])
+
+	for _, line := range splitVCardValue(components[2], ',') {
+		if line != "" {
+			adr.StreetAddress = append(adr.StreetAddress, unescapeVCardValue(line))
+		}
+	}
+
+	adr.Locality = unescapeVCardValue(components[3])
+	adr.Region = unescapeVCardValue(components[4])
+	adr.PostCode = unescapeVCardValue(components[5])
+	adr.Country = unescapeVCardValue(components[6])
+
+	return adr, nil
+}
+
+// JCard returns the ADR property as a jCard (RFC 7095) property, such as
+// `["adr",{"label":"..."},"text",["","","525 Collins Street","Melbourne","VIC","3000","Australia"]]`.
+func (v VCardADR) JCard() ([]byte, error) {
+
+	parameters := map[string]string{}
+
+	if v.Label != "" {
+		parameters["label"] = v.Label
+	}
+
+	var street interface{} = ""
+
+	if len(v.StreetAddress) == 1 {
+		street = v.StreetAddress[0]
+	} else if len(v.StreetAddress) > 1 {
+		street = v.StreetAddress
+	}
+
+	return json.Marshal([]interface{}{
+		"adr",
+		parameters,
+		"text",
+		[]interface{}{v.POBox, v.ExtendedAddress, street, v.Locality, v.Region, v.PostCode, v.Country},
+	})
+}
+
+// ParseJCardADR parses a jCard (RFC 7095) adr property.
+func ParseJCardADR(property []byte) (VCardADR, error) {
+
+	adr := VCardADR{}
+
+	var raw []json.RawMessage
+
+	if err := json.Unmarshal(property, &raw); err != nil {
+		return adr, fmt.Errorf("%w: %w", ErrInvalidVCardADR, err)
+	}
+
+	if len(raw) != 4 {
+		return adr, fmt.Errorf("%w: expected 4 elements, got %d", ErrInvalidVCardADR, len(raw))
+	}
+
+	var name string
+
+	if err := json.Unmarshal(raw[0], &name); err != nil || !strings.EqualFold(name, "adr") {
+		return adr, fmt.Errorf("%w: expected an adr property", ErrInvalidVCardADR)
+	}
+
+	var parameters map[string]interface{}
+
+	if err := json.Unmarshal(raw[1], &parameters); err != nil {
+		return adr, fmt.Errorf("%w: %w", ErrInvalidVCardADR, err)
+	}
+
+	for key, value := range parameters {
+		if label, ok := value.(string); ok && strings.EqualFold(key, "label") {
+			adr.Label = label
+		}
+	}
+
+	var components []json.RawMessage
+
+	if err := json.Unmarshal(raw[3], &components); err != nil {
+		return adr, fmt.Errorf("%w: %w", ErrInvalidVCardADR, err)
+	}
+
+	if len(components) != 7 {
+		return adr, fmt.Errorf("%w: expected 7 components, got %d", ErrInvalidVCardADR, len(components))
+	}
+
+	values := make([][]string, len(components))
+
+	for i, component := range components {
+
+		var single string
+
+		if err := json.Unmarshal(component, &single); err == nil {
+			values[i] = []string{single}
+			continue
+		}
+
+		if err := json.Unmarshal(component, &values[i]); err != nil {
+			return adr, fmt.Errorf("%w: %w", ErrInvalidVCardADR, err)
+		}
+	}
+
+	adr.POBox = strings.Join(values[0], ", ")
+	adr.ExtendedAddress = strings.Join(values[1], ", ")
+
+	for _, line := range values[2] {
+		if line != "" {
+			adr.StreetAddress = append(adr.StreetAddress, line)
+		}
+	}
+
+	adr.Locality = strings.Join(values[3], ", ")
+	adr.Region = strings.Join(values[4], ", ")
+	adr.PostCode = strings.Join(values[5], ", ")
+	adr.Country = strings.Join(values[6], ", ")
+
+	return adr, nil
+}
+
+var vCardValueEscaper = strings.NewReplacer(`\`, `\\`, `,`, `\,`, `;`, `\;`, "\r\n", `\n`, "\n", `\n`)
+
+func escapeVCardValue(value string) string {
+	return vCardValueEscaper.Replace(value)
+}
+
+func unescapeVCardValue(value string) string {
+
+	var b strings.Builder
+
+	escaped := false
+
+	for _, r := range value {
+
+		if escaped {
+			if r == 'n' || r == 'N' {
+				b.WriteRune('\n')
+			} else {
+				b.WriteRune(r)
+			}
+
+			escaped = false
+			continue
+		}
+
+		if r == '\\' {
+			escaped = true
+			continue
+		}
+
+		b.WriteRune(r)
+	}
+
+	return b.String()
+}
+
+// splitVCardValue splits a value on a separator, ignoring escaped separators. The parts are not unescaped.
+func splitVCardValue(value string, separator rune) []string {
+
+	var parts []string
+
+	var current strings.Builder
+
+	escaped := false
+
+	for _, r := range value {
+
+		switch {
+		case escaped:
+			current.WriteRune('\\')
+			current.WriteRune(r)
+			escaped = false
+
+		case r == '\\':
+			escaped = true
+
+		case r == separator:
+			parts = append(parts, current.String())
+			current.Reset()
+
+		default:
+			current.WriteRune(r)
+		}
+	}
+
+	return append(parts, current.String())
+}
+
+// Parameter values are encoded as described in RFC 6868.
+var vCardParameterEscaper = strings.NewReplacer("^", "^^", "\r\n", "^n", "\n", "^n", `"`, "^'")
+
+var vCardParameterUnescaper = strings.NewReplacer("^^", "^", "^n", "\n", "^'", `"`)
+
+func escapeVCardParameter(value string) string {
+	return vCardParameterEscaper.Replace(value)
+}
+
+// splitVCardContentLine splits a content line into the property name and the rest of the line (parameters and value).
+func splitVCardContentLine(line string) (string, string, error) {
+
+	idx := strings.IndexAny(line, ";:")
+
+	if idx == -1 {
+		return "", "", fmt.Errorf("%w: missing value", ErrInvalidVCardADR)
+	}
+
+	return line[:idx], line[idx:], nil
+}
+
+// splitVCardParameters parses the parameters at the start of a content line and returns them along with the value.
+func splitVCardParameters(rest string) (map[string]string, string, error) {
+
+	parameters := map[string]string{}
+
+	for strings.HasPrefix(rest, ";") {
+
+		rest = rest[1:]
+
+		idx := strings.Index(rest, "=")
+
+		if idx == -1 {
+			return parameters, "", fmt.Errorf("%w: invalid parameter", ErrInvalidVCardADR)
+		}
+
+		name := strings.ToUpper(rest[:idx])
+		rest = rest[idx+1:]
+
+		var value string
+
+		if strings.HasPrefix(rest, `"`) {
+
+			end := strings.Index(rest[1:], `"`)
+
+			if end == -1 {
+				return parameters, "", fmt.Errorf("%w: unterminated parameter value", ErrInvalidVCardADR)
+			}
+
+			value = rest[1 : end+1]
+			rest = rest[end+2:]
+		} else {
+
+			end := strings.IndexAny(rest, ";:")
+
+			if end == -1 {
+				return parameters, "", fmt.Errorf("%w: missing value", ErrInvalidVCardADR)
+			}
+
+			value = rest[:end]
+			rest = rest[end:]
+		}
+
+		parameters[name] = vCardParameterUnescaper.Replace(value)
+	}
+
+	if !strings.HasPrefix(rest, ":") {
+		return parameters, "", fmt.Errorf("%w: missing value", ErrInvalidVCardADR)
+	}
+
+	return parameters, rest[1:], nil
+}
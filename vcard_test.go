@@ -0,0 +1,197 @@
+package address
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestToVCardADR(t *testing.T) {
+
+	address, err := NewValid(
+		WithName("John Smith"),
+		WithStreetAddress([]string{
+			"Suite 7; 9th Floor",
+			"525 Collins Street",
+		}),
+		WithLocality("Melbourne"),
+		WithAdministrativeArea("VIC"),
+		WithPostCode("3000"),
+		WithCountry("AU"),
+	)
+
+	if err != nil {
+		t.Fatalf("Error creating valid address: %s", err)
+	}
+
+	testCases := []struct {
+		Options          VCardOptions
+		ExpectedProperty string
+		ExpectedJCard    string
+	}{
+		{
+			Options:          VCardOptions{Language: "en"},
+			ExpectedProperty: `ADR:;;Suite 7\; 9th Floor,525 Collins Street;Melbourne;Victoria;3000;Australia`,
+			ExpectedJCard:    `["adr",{},"text",["","",["Suite 7; 9th Floor","525 Collins Street"],"Melbourne","Victoria","3000","Australia"]]`,
+		},
+		{
+			Options: VCardOptions{
+				Language:                 "en",
+				UseAdministrativeAreaKey: true,
+				Label:                    PostalLabelFormatter{Output: StringOutputter{}, OriginCountryCode: "AU"},
+			},
+			ExpectedProperty: `ADR;LABEL="John Smith^nSuite 7; 9th Floor^n525 Collins Street^nMELBOURNE VIC 3000":;;Suite 7\; 9th Floor,525 Collins Street;Melbourne;VIC;3000;Australia`,
+			ExpectedJCard:    `["adr",{"label":"John Smith\nSuite 7; 9th Floor\n525 Collins Street\nMELBOURNE VIC 3000"},"text",["","",["Suite 7; 9th Floor","525 Collins Street"],"Melbourne","VIC","3000","Australia"]]`,
+		},
+	}
+
+	for i, testCase := range testCases {
+
+		adr := ToVCardADR(address, testCase.Options)
+
+		if adr.Property() != testCase.ExpectedProperty {
+			t.Errorf("ADR property for test case %d does not match the expected result, got %s", i, adr.Property())
+		}
+
+		jCard, err := adr.JCard()
+
+		if err != nil {
+			t.Fatalf("Error converting test case %d to jCard: %s", i, err)
+		}
+
+		if string(jCard) != testCase.ExpectedJCard {
+			t.Errorf("jCard for test case %d does not match the expected result, got %s", i, jCard)
+		}
+
+		parsed, err := ParseVCardADR(adr.Property())
+
+		if err != nil {
+			t.Fatalf("Error parsing ADR property for test case %d: %s", i, err)
+		}
+
+		if !reflect.DeepEqual(parsed, adr) {
+			t.Errorf("Parsed ADR property for test case %d does not match, got %+v", i, parsed)
+		}
+
+		parsed, err = ParseJCardADR(jCard)
+
+		if err != nil {
+			t.Fatalf("Error parsing jCard for test case %d: %s", i, err)
+		}
+
+		if !reflect.DeepEqual(parsed, adr) {
+			t.Errorf("Parsed jCard for test case %d does not match, got %+v", i, parsed)
+		}
+
+		converted, err := parsed.ToAddress()
+
+		if err != nil {
+			t.Fatalf("Error converting test case %d to an address: %s", i, err)
+		}
+
+		expected := address
+		expected.Name = ""
+
+		if !reflect.DeepEqual(converted, expected) {
+			t.Errorf("Converted address for test case %d does not match, got %+v", i, converted)
+		}
+	}
+}
+
+func TestToVCardADRLabelWithSpecialCharacters(t *testing.T) {
+
+	address := New(
+		WithName("Tom O'Brien"),
+		WithOrganization("O'Brien & Co"),
+		WithStreetAddress([]string{
+			"525 <Collins> Street",
+		}),
+		WithLocality("Melbourne"),
+		WithAdministrativeArea("VIC"),
+		WithPostCode("3000"),
+		WithCountry("AU"),
+	)
+
+	adr := ToVCardADR(address, VCardOptions{
+		Language: "en",
+		Label:    PostalLabelFormatter{Output: StringOutputter{}, OriginCountryCode: "AU"},
+	})
+
+	expected := "O'Brien & Co\nTom O'Brien\n525 <Collins> Street\nMELBOURNE VIC 3000"
+
+	if adr.Label != expected {
+		t.Errorf("ADR label does not match the expected result, got %q", adr.Label)
+	}
+}
+
+func TestParseVCardADR(t *testing.T) {
+
+	testCases := []struct {
+		Property    string
+		Expected    Address
+		ExpectedErr error
+	}{
+		{
+			Property: `ADR;TYPE=work:;Suite 7;1 Microsoft Way;Redmond;Washington;98052;United States`,
+			Expected: Address{
+				Country:            "US",
+				StreetAddress:      []string{"Suite 7", "1 Microsoft Way"},
+				Locality:           "Redmond",
+				AdministrativeArea: "WA",
+				PostCode:           "98052",
+			},
+		},
+		{
+			Property: `;;1 西河北路;临沧市;云南省;677000;CN`,
+			Expected: Address{
+				Country:            "CN",
+				StreetAddress:      []string{"1 西河北路"},
+				Locality:           "临沧市",
+				AdministrativeArea: "53",
+				PostCode:           "677000",
+			},
+		},
+		{
+			Property: `;;1 Rue;Québec;Québec;G1R 4S9;Canada`,
+			Expected: Address{
+				Country:            "CA",
+				StreetAddress:      []string{"1 Rue"},
+				Locality:           "Québec",
+				AdministrativeArea: "QC",
+				PostCode:           "G1R 4S9",
+			},
+		},
+		{
+			Property:    `;;1 Rue;Paris;;75001`,
+			ExpectedErr: ErrInvalidVCardADR,
+		},
+		{
+			Property:    `ADR;LABEL="unterminated:;;1 Rue;Paris;;75001;France`,
+			ExpectedErr: ErrInvalidVCardADR,
+		},
+		{
+			Property:    `;;1 Rue;Paris;;75001;Atlantis`,
+			ExpectedErr: ErrInvalidCountryCode,
+		},
+	}
+
+	for i, testCase := range testCases {
+
+		adr, err := ParseVCardADR(testCase.Property)
+
+		var address Address
+
+		if err == nil {
+			address, err = adr.ToAddress()
+		}
+
+		if !errors.Is(err, testCase.ExpectedErr) {
+			t.Errorf("Expected error %v for test case %d, got %v", testCase.ExpectedErr, i, err)
+			continue
+		}
+
+		if testCase.ExpectedErr == nil && !reflect.DeepEqual(address, testCase.Expected) {
+			t.Errorf("Parsed address for test case %d does not match the expected result, got %+v", i, address)
+		}
+	}
+}
@@ -0,0 +1,304 @@
+package address
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// XALNamespace is the XML namespace of OASIS xAL 2.0 documents.
+const XALNamespace = "urn:oasis:names:tc:ciq:xsdschema:xAL:2.0"
+
+// XALAddressDetails is an OASIS xAL 2.0 AddressDetails element. Only the elements needed to represent an address are
+// included. The name and organization of the addressee are not part of xAL, so they are not included.
+type XALAddressDetails struct {
+	XMLName               xml.Name                  `xml:"urn:oasis:names:tc:ciq:xsdschema:xAL:2.0 AddressDetails"`
+	PostalServiceElements *XALPostalServiceElements `xml:"PostalServiceElements,omitempty"`
+	Country               XALCountry                `xml:"Country"`
+}
+
+// XALPostalServiceElements contains the sorting code of an address.
+type XALPostalServiceElements struct {
+	SortingCode *XALSortingCode `xml:"SortingCode,omitempty"`
+}
+
+// XALSortingCode contains the sorting code of an address in its Type attribute, as required by xAL.
+type XALSortingCode struct {
+	Type string `xml:"Type,attr"`
+}
+
+// XALCountry is the country of an address.
+type XALCountry struct {
+	CountryNameCode    string                 `xml:"CountryNameCode"`
+	CountryName        string                 `xml:"CountryName,omitempty"`
+	AdministrativeArea *XALAdministrativeArea `xml:"AdministrativeArea,omitempty"`
+	Locality           *XALLocality           `xml:"Locality,omitempty"`
+}
+
+// XALAdministrativeArea is the administrative area of an address.
+type XALAdministrativeArea struct {
+	AdministrativeAreaName string         `xml:"AdministrativeAreaName"`
+	Locality               *XALLocality   `xml:"Locality,omitempty"`
+	PostalCode             *XALPostalCode `xml:"PostalCode,omitempty"`
+}
+
+// XALLocality is the locality of an address. It contains the street address, the dependent locality and the
+// post code.
+type XALLocality struct {
+	LocalityName      string                `xml:"LocalityName,omitempty"`
+	Thoroughfare      *XALThoroughfare      `xml:"Thoroughfare,omitempty"`
+	DependentLocality *XALDependentLocality `xml:"DependentLocality,omitempty"`
+	PostalCode        *XALPostalCode        `xml:"PostalCode,omitempty"`
+}
+
+// XALThoroughfare contains the street address lines of an address.
+type XALThoroughfare struct {
+	ThoroughfareName []string `xml:"ThoroughfareName"`
+}
+
+// XALDependentLocality is the dependent locality of an address.
+type XALDependentLocality struct {
+	DependentLocalityName string `xml:"DependentLocalityName"`
+}
+
+// XALPostalCode contains the post code of an address.
+type XALPostalCode struct {
+	PostalCodeNumber string `xml:"PostalCodeNumber"`
+}
+
+// ToXAL converts an address into an xAL 2.0 AddressDetails element. The country data is used to get the names of the
+// administrative area, locality and dependent locality in the given language. If the language does not have any
+// translations, the country's default language is used.
+func ToXAL(address Address, countryData CountryData, language string) XALAddressDetails {
+	return defaultRegistry.ToXAL(address, countryData, language)
+}
+
+// ToXAL converts an address into an xAL 2.0 AddressDetails element, using the registry's data for the name of the
+// country. See ToXAL for details.
+func (r *Registry) ToXAL(address Address, countryData CountryData, language string) XALAddressDetails {
+
+	names := countryData.subdivisionNames(address, language)
+
+	details := XALAddressDetails{
+		Country: XALCountry{
+			CountryNameCode: address.Country,
+			CountryName:     r.countryName(address.Country, language),
+		},
+	}
+
+	if address.SortingCode != "" {
+		details.PostalServiceElements = &XALPostalServiceElements{
+			SortingCode: &XALSortingCode{
+				Type: address.SortingCode,
+			},
+		}
+	}
+
+	locality := &XALLocality{
+		LocalityName: names.locality,
+	}
+
+	var streetAddress []string
+
+	for _, line := range address.StreetAddress {
+		if strings.TrimSpace(line) != "" {
+			streetAddress = append(streetAddress, strings.TrimSpace(line))
+		}
+	}
+
+	if len(streetAddress) > 0 {
+		locality.Thoroughfare = &XALThoroughfare{
+			ThoroughfareName: streetAddress,
+		}
+	}
+
+	if names.dependentLocality != "" {
+		locality.DependentLocality = &XALDependentLocality{
+			DependentLocalityName: names.dependentLocality,
+		}
+	}
+
+	if address.PostCode != "" {
+		locality.PostalCode = &XALPostalCode{
+			PostalCodeNumber: address.PostCode,
+		}
+	}
+
+	if *locality == (XALLocality{}) {
+		locality = nil
+	}
+
+	if names.administrativeArea != "" {
+		details.Country.AdministrativeArea = &XALAdministrativeArea{
+			AdministrativeAreaName: names.administrativeArea,
+			Locality:               locality,
+		}
+	} else {
+		details.Country.Locality = locality
+	}
+
+	return details
+}
+
+// EncodeXAL writes an address as an xAL 2.0 AddressDetails document. See ToXAL for details.
+func EncodeXAL(w io.Writer, address Address, countryData CountryData, language string) error {
+	return defaultRegistry.EncodeXAL(w, address, countryData, language)
+}
+
+// EncodeXAL writes an address as an xAL 2.0 AddressDetails document using the registry's data. See ToXAL for details.
+func (r *Registry) EncodeXAL(w io.Writer, address Address, countryData CountryData, language string) error {
+
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+
+	return encoder.Encode(r.ToXAL(address, countryData, language))
+}
+
+// DecodeXAL reads an xAL 2.0 AddressDetails document and converts it into an address. The administrative area,
+// locality and dependent locality are converted into their keys where possible, and the address is validated.
+// In the case where the address is invalid, the address is returned along with the validation error.
+func DecodeXAL(r io.Reader) (Address, error) {
+	return defaultRegistry.DecodeXAL(r)
+}
+
+// DecodeXAL reads an xAL 2.0 AddressDetails document and converts it into an address using the registry's data and
+// overrides. See DecodeXAL for details.
+func (r *Registry) DecodeXAL(reader io.Reader) (Address, error) {
+
+	details := XALAddressDetails{}
+
+	if err := xml.NewDecoder(reader).Decode(&details); err != nil {
+		return Address{}, fmt.Errorf("%w: %w", ErrInvalidXML, err)
+	}
+
+	return r.AddressFromXAL(details)
+}
+
+// ToAddress converts the xAL AddressDetails into an address. See DecodeXAL for details.
+func (x XALAddressDetails) ToAddress() (Address, error) {
+	return defaultRegistry.AddressFromXAL(x)
+}
+
+// AddressFromXAL converts xAL AddressDetails into an address using the registry's data and overrides. See DecodeXAL
+// for details.
+func (r *Registry) AddressFromXAL(x XALAddressDetails) (Address, error) {
+
+	countryCode := r.data.getCountryCode(x.Country.CountryNameCode)
+
+	if countryCode == "" {
+		countryCode = r.data.getCountryCode(x.Country.CountryName)
+	}
+
+	if countryCode == "" {
+		return Address{}, ErrInvalidCountryCode
+	}
+
+	address := Address{
+		Country: countryCode,
+	}
+
+	locality := x.Country.Locality
+
+	if adminArea := x.Country.AdministrativeArea; adminArea != nil {
+
+		address.AdministrativeArea = strings.TrimSpace(adminArea.AdministrativeAreaName)
+
+		if adminArea.Locality != nil {
+			locality = adminArea.Locality
+		}
+
+		if adminArea.PostalCode != nil {
+			address.PostCode = strings.TrimSpace(adminArea.PostalCode.PostalCodeNumber)
+		}
+	}
+
+	if locality != nil {
+
+		address.Locality = strings.TrimSpace(locality.LocalityName)
+
+		if locality.Thoroughfare != nil {
+			for _, line := range locality.Thoroughfare.ThoroughfareName {
+				if strings.TrimSpace(line) != "" {
+					address.StreetAddress = append(address.StreetAddress, strings.TrimSpace(line))
+				}
+			}
+		}
+
+		if locality.DependentLocality != nil {
+			address.DependentLocality = strings.TrimSpace(locality.DependentLocality.DependentLocalityName)
+		}
+
+		if locality.PostalCode != nil {
+			address.PostCode = strings.TrimSpace(locality.PostalCode.PostalCodeNumber)
+		}
+	}
+
+	if x.PostalServiceElements != nil && x.PostalServiceElements.SortingCode != nil {
+		address.SortingCode = strings.TrimSpace(x.PostalServiceElements.SortingCode.Type)
+	}
+
+	return r.resolveSubdivisionsAndValidate(address)
+}
+
+// resolveSubdivisionsAndValidate converts the names of the subdivisions of an address into their keys where possible
+// and validates the address.
+func (r *Registry) resolveSubdivisionsAndValidate(address Address) (Address, error) {
+
+	address = r.data.resolveSubdivisions(address)
+
+	if err := r.Validate(address); err != nil {
+		return address, fmt.Errorf("invalid address: %w", err)
+	}
+
+	return address, nil
+}
+
+type subdivisionNames struct {
+	administrativeArea string
+	locality           string
+	dependentLocality  string
+}
+
+// subdivisionNames returns the names of the subdivisions of an address in the given language. If a subdivision
+// cannot be found in the country data, the value from the address is used.
+func (c CountryData) subdivisionNames(address Address, language string) subdivisionNames {
+
+	names := subdivisionNames{
+		administrativeArea: strings.TrimSpace(address.AdministrativeArea),
+		locality:           strings.TrimSpace(address.Locality),
+		dependentLocality:  strings.TrimSpace(address.DependentLocality),
+	}
+
+	adminAreas, ok := c.AdministrativeAreas[language]
+
+	if !ok {
+		adminAreas = c.AdministrativeAreas[c.DefaultLanguage]
+	}
+
+	for _, adminArea := range adminAreas {
+
+		if adminArea.ID != address.AdministrativeArea {
+			continue
+		}
+
+		names.administrativeArea = adminArea.Name
+
+		for _, locality := range adminArea.Localities {
+
+			if locality.ID != address.Locality {
+				continue
+			}
+
+			names.locality = locality.Name
+
+			for _, dependentLocality := range locality.DependentLocalities {
+				if dependentLocality.ID == address.DependentLocality {
+					names.dependentLocality = dependentLocality.Name
+				}
+			}
+		}
+	}
+
+	return names
+}
@@ -0,0 +1,170 @@
+package address
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestXAL(t *testing.T) {
+
+	testCases := []struct {
+		Address  []func(*Address)
+		Expected string
+	}{
+		{
+			Address: []func(*Address){
+				WithStreetAddress([]string{
+					"525 Collins Street",
+				}),
+				WithLocality("Melbourne"),
+				WithAdministrativeArea("VIC"),
+				WithPostCode("3000"),
+				WithCountry("AU"),
+			},
+			Expected: `<AddressDetails xmlns="urn:oasis:names:tc:ciq:xsdschema:xAL:2.0">
+  <Country>
+    <CountryNameCode>AU</CountryNameCode>
+    <CountryName>Australia</CountryName>
+    <AdministrativeArea>
+      <AdministrativeAreaName>Victoria</AdministrativeAreaName>
+      <Locality>
+        <LocalityName>Melbourne</LocalityName>
+        <Thoroughfare>
+          <ThoroughfareName>525 Collins Street</ThoroughfareName>
+        </Thoroughfare>
+        <PostalCode>
+          <PostalCodeNumber>3000</PostalCodeNumber>
+        </PostalCode>
+      </Locality>
+    </AdministrativeArea>
+  </Country>
+</AddressDetails>`,
+		},
+		{
+			Address: []func(*Address){
+				WithStreetAddress([]string{
+					"2 Avenue de Monte-Carlo",
+				}),
+				WithLocality("Monaco"),
+				WithPostCode("98000"),
+				WithSortingCode("CEDEX"),
+				WithCountry("MC"),
+			},
+			Expected: `<AddressDetails xmlns="urn:oasis:names:tc:ciq:xsdschema:xAL:2.0">
+  <PostalServiceElements>
+    <SortingCode Type="CEDEX"></SortingCode>
+  </PostalServiceElements>
+  <Country>
+    <CountryNameCode>MC</CountryNameCode>
+    <CountryName>Monaco</CountryName>
+    <Locality>
+      <LocalityName>Monaco</LocalityName>
+      <Thoroughfare>
+        <ThoroughfareName>2 Avenue de Monte-Carlo</ThoroughfareName>
+      </Thoroughfare>
+      <PostalCode>
+        <PostalCodeNumber>98000</PostalCodeNumber>
+      </PostalCode>
+    </Locality>
+  </Country>
+</AddressDetails>`,
+		},
+		{
+			Address: []func(*Address){
+				WithStreetAddress([]string{
+					"1 西河北路",
+				}),
+				WithDependentLocality("临翔区"),
+				WithLocality("临沧市"),
+				WithAdministrativeArea("53"),
+				WithPostCode("677000"),
+				WithCountry("CN"),
+			},
+			Expected: `<AddressDetails xmlns="urn:oasis:names:tc:ciq:xsdschema:xAL:2.0">
+  <Country>
+    <CountryNameCode>CN</CountryNameCode>
+    <CountryName>China</CountryName>
+    <AdministrativeArea>
+      <AdministrativeAreaName>Yunnan Sheng</AdministrativeAreaName>
+      <Locality>
+        <LocalityName>Lincang Shi</LocalityName>
+        <Thoroughfare>
+          <ThoroughfareName>1 西河北路</ThoroughfareName>
+        </Thoroughfare>
+        <DependentLocality>
+          <DependentLocalityName>Linxiang Qu</DependentLocalityName>
+        </DependentLocality>
+        <PostalCode>
+          <PostalCodeNumber>677000</PostalCodeNumber>
+        </PostalCode>
+      </Locality>
+    </AdministrativeArea>
+  </Country>
+</AddressDetails>`,
+		},
+	}
+
+	for i, testCase := range testCases {
+
+		address, err := NewValid(testCase.Address...)
+
+		if err != nil {
+			t.Fatalf("Error creating valid address using test case %d: %s", i, err)
+		}
+
+		buf := bytes.NewBuffer([]byte{})
+
+		err = EncodeXAL(buf, address, GetCountry(address.Country), "en")
+
+		if err != nil {
+			t.Fatalf("Error encoding test case %d: %s", i, err)
+		}
+
+		if buf.String() != testCase.Expected {
+			t.Errorf("xAL for test case %d does not match the expected result, got %s", i, buf.String())
+		}
+
+		decoded, err := DecodeXAL(buf)
+
+		if err != nil {
+			t.Fatalf("Error decoding test case %d: %s", i, err)
+		}
+
+		if !reflect.DeepEqual(decoded, address) {
+			t.Errorf("Decoded address for test case %d does not match, got %+v", i, decoded)
+		}
+	}
+}
+
+func TestDecodeXALErrors(t *testing.T) {
+
+	testCases := []struct {
+		XML         string
+		ExpectedErr error
+	}{
+		{
+			XML:         `<AddressDetails xmlns="urn:oasis:names:tc:ciq:xsdschema:xAL:2.0"><Country>`,
+			ExpectedErr: ErrInvalidXML,
+		},
+		{
+			XML:         `<AddressDetails xmlns="urn:oasis:names:tc:ciq:xsdschema:xAL:2.0"><Country><CountryNameCode>XX</CountryNameCode></Country></AddressDetails>`,
+			ExpectedErr: ErrInvalidCountryCode,
+		},
+		{
+			XML:         `<AddressDetails xmlns="urn:oasis:names:tc:ciq:xsdschema:xAL:2.0"><Country><CountryNameCode>AU</CountryNameCode><AdministrativeArea><AdministrativeAreaName>Atlantis</AdministrativeAreaName></AdministrativeArea></Country></AddressDetails>`,
+			ExpectedErr: ErrInvalidAdministrativeArea,
+		},
+	}
+
+	for i, testCase := range testCases {
+
+		_, err := DecodeXAL(strings.NewReader(testCase.XML))
+
+		if !errors.Is(err, testCase.ExpectedErr) {
+			t.Errorf("Expected error %v for test case %d, got %v", testCase.ExpectedErr, i, err)
+		}
+	}
+}